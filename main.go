@@ -1,14 +1,14 @@
 package main
 
 import (
+	"errors"
 	"log"
 	"os"
+	"pm-report/models"
 	"pm-report/services"
 )
 
 func main() {
-	log.Println("Report creating started")
-
 	// args
 	inputArgsService := services.NewInputArgsService()
 
@@ -18,6 +18,15 @@ func main() {
 		return
 	}
 
+	// conversion does not need app config
+	if inputArgs.Command == models.ConvertCommand {
+		err = convertProjectConfig(inputArgs)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// config
 	appConfigService := services.NewAppConfigService(inputArgs.AppConfig)
 
@@ -27,26 +36,164 @@ func main() {
 		return
 	}
 
+	switch inputArgs.Command {
+	case models.CheckTokensCommand:
+		err = checkTokens(appConfig)
+	default:
+		err = createReport(inputArgs, appConfig)
+	}
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+}
+
+func createReport(inputArgs *models.InputArgs, appConfig *models.AppConfig) error {
+	log.Println("Report creating started")
+
 	// get data
+	worklogServiceFactory := services.NewWorklogServiceFactory(appConfig.Tempo, appConfig.Jira)
+	jiraService := services.NewJiraService(appConfig.Jira)
+	projectConfigService := services.NewProjectConfigService(appConfig.Files.ProjectConfigFile, appConfig.Files.MarkInactiveProjects, appConfig.Files.ArchiveAfterMonths, inputArgs.DryRun, jiraService)
+
+	// validate project config
+	issues, err := projectConfigService.Validate(appConfig.Tempo)
+	if err != nil {
+		return err
+	}
+	log.Println("Project config issues found:", len(issues))
+	if len(issues) > 0 {
+		err = projectConfigService.PrintIssues(os.Stdout, issues)
+		if err != nil {
+			return err
+		}
+		if inputArgs.Strict {
+			return errors.New("error: project config has issues, report is not created in strict mode")
+		}
+	}
+
 	reportService := services.NewReportService(
-		services.NewProjectConfigService(appConfig.Files.ProjectConfigFile),
-		services.NewTempoService(appConfig.Tempo.Url),
-		appConfig.Tempo.Tokens)
+		projectConfigService,
+		worklogServiceFactory,
+		jiraService,
+		appConfig.Tempo)
 
-	report, err := reportService.Create(inputArgs.DateFrom, inputArgs.DateTo)
+	dateFrom, dateTo := inputArgs.DateFrom, inputArgs.DateTo
+
+	// sprint mode takes the period from sprint dates
+	sprintService := services.NewSprintService(jiraService)
+	var sprint *models.Sprint
+	if inputArgs.Command == models.SprintCommand {
+		sprint, err = sprintService.Get(inputArgs.BoardId, inputArgs.Sprint)
+		if err != nil {
+			return err
+		}
+		dateFrom, dateTo = sprint.DateFrom, sprint.DateTo
+	}
+
+	report, err := reportService.Create(dateFrom, dateTo)
 	if err != nil {
-		log.Fatal(err)
-		return
+		return err
+	}
+	worklogServiceFactory.LogStats()
+
+	if sprint != nil {
+		err = sprintService.Fill(sprint, report)
+		if err != nil {
+			return err
+		}
+	}
+
+	allocationService := services.NewAllocationService(appConfig.Allocation)
+	if allocationService.IsEnabled() && !jiraService.IsEnabled() {
+		// components and labels come from Jira, all costs would go to default category
+		log.Println("Warning: allocation rules require jira.url, allocation is skipped")
+	} else if allocationService.IsEnabled() {
+		allocationService.Fill(report)
+	}
+
+	if jiraService.IsEnabled() {
+		hygieneService := services.NewHygieneService()
+		hygieneService.Check(report)
+
+		log.Println("Worklog hygiene violations found:", len(report.Violations))
+		err = hygieneService.PrintSummary(os.Stdout, report)
+		if err != nil {
+			return err
+		}
 	}
 
 	// save data
 	excelService := services.NewExcelService(appConfig.Files.ReportFile)
 
+	if inputArgs.DryRun {
+		log.Println("Dry run, report is not saved:")
+		err = excelService.PrintPreview(os.Stdout, report)
+		if err != nil {
+			return err
+		}
+
+		log.Println("Report creating finished successfully (dry run)")
+		return nil
+	}
+
 	err = excelService.Save(report)
 	if err != nil {
-		log.Fatal(err)
-		return
+		return err
+	}
+
+	if len(appConfig.Files.ExportFile) > 0 {
+		exportService := services.NewExportService(appConfig.Files.ExportFile)
+
+		err = exportService.Save(report)
+		if err != nil {
+			return err
+		}
 	}
 
 	log.Println("Report creating finished successfully")
+
+	return nil
+}
+
+func checkTokens(appConfig *models.AppConfig) error {
+	log.Println("Tokens checking started")
+
+	worklogServiceFactory := services.NewWorklogServiceFactory(appConfig.Tempo, appConfig.Jira)
+	tokenCheckService := services.NewTokenCheckService(
+		worklogServiceFactory,
+		appConfig.Tempo.Tokens)
+
+	tokenChecks := tokenCheckService.Check()
+	worklogServiceFactory.LogStats()
+
+	err := tokenCheckService.Print(os.Stdout, tokenChecks)
+	if err != nil {
+		return err
+	}
+
+	for _, tokenCheck := range tokenChecks {
+		if tokenCheck.HasProblems() {
+			return errors.New("error: some tokens are invalid or cannot read configured projects")
+		}
+	}
+
+	log.Println("Tokens checking finished successfully")
+
+	return nil
+}
+
+func convertProjectConfig(inputArgs *models.InputArgs) error {
+	log.Println("Project config converting started")
+
+	projectConfigConvertService := services.NewProjectConfigConvertService()
+
+	err := projectConfigConvertService.Convert(inputArgs.SourceFile, inputArgs.TargetFile)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Project config converting finished successfully")
+
+	return nil
 }
@@ -1,21 +1,81 @@
 package models
 
+const (
+	ProjectFetchStrategy      = "project"
+	OrganizationFetchStrategy = "organization"
+
+	OffDiscoveryMode     = "off"
+	WarnDiscoveryMode    = "warn"
+	IncludeDiscoveryMode = "include"
+
+	TempoWorklogSource = "tempo"
+	JiraWorklogSource  = "jira"
+)
+
 type AppConfig struct {
-	Files FilesAppConfig `mapstructure:"files"`
-	Tempo TempoAppConfig `mapstructure:"tempo"`
+	Files      FilesAppConfig      `mapstructure:"files"`
+	Tempo      TempoAppConfig      `mapstructure:"tempo"`
+	Jira       JiraAppConfig       `mapstructure:"jira"`
+	Allocation AllocationAppConfig `mapstructure:"allocation"`
 }
 
 type FilesAppConfig struct {
 	ProjectConfigFile string `mapstructure:"project_config"`
 	ReportFile        string `mapstructure:"report"`
+	ExportFile        string `mapstructure:"export"` // optional, machine-readable (json) report
+
+	MarkInactiveProjects bool `mapstructure:"mark_inactive_projects"` // mark project configs which are not in the run
+	ArchiveAfterMonths   int  `mapstructure:"archive_after_months"`   // archive users without worklogs, 0 disables
 }
 
 type TempoAppConfig struct {
-	Url    string                `mapstructure:"url"`
-	Tokens []TokenTempoAppConfig `mapstructure:"tokens"`
+	Url       string                  `mapstructure:"url"`
+	Strategy  string                  `mapstructure:"strategy"`
+	Discovery DiscoveryTempoAppConfig `mapstructure:"discovery"`
+	RateLimit RateLimitTempoAppConfig `mapstructure:"rate_limit"`
+	Tokens    []TokenTempoAppConfig   `mapstructure:"tokens"`
+}
+
+type DiscoveryTempoAppConfig struct {
+	Mode  string `mapstructure:"mode"`
+	Allow string `mapstructure:"allow"`
+	Deny  string `mapstructure:"deny"`
+}
+
+type RateLimitTempoAppConfig struct {
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"` // 0 disables rate limiting
+	Burst             int     `mapstructure:"burst"`
 }
 
 type TokenTempoAppConfig struct {
+	Source   string `mapstructure:"source"` // optional, tempo (default) or jira
+	Url      string `mapstructure:"url"`    // optional, overrides tempo url (or jira url for jira source)
+	Email    string `mapstructure:"email"`  // optional, overrides jira email for jira source
 	Token    string `mapstructure:"token"`
 	Projects string `mapstructure:"projects"`
 }
+
+type JiraAppConfig struct {
+	Url           string `mapstructure:"url"` // optional, jira integration is disabled if empty
+	Email         string `mapstructure:"email"`
+	Token         string `mapstructure:"token"`
+	ResolveEmails bool   `mapstructure:"resolve_emails"`
+
+	Filters []FilterJiraAppConfig `mapstructure:"filters"`
+}
+
+type FilterJiraAppConfig struct {
+	Project string `mapstructure:"project"`
+	Jql     string `mapstructure:"jql"` // only worklogs of issues matching the expression are counted
+}
+
+type AllocationAppConfig struct {
+	Default string                    `mapstructure:"default"` // category of issues matching no rule
+	Rules   []RuleAllocationAppConfig `mapstructure:"rules"`
+}
+
+type RuleAllocationAppConfig struct {
+	Category   string `mapstructure:"category"`
+	Components string `mapstructure:"components"`
+	Labels     string `mapstructure:"labels"`
+}
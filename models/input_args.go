@@ -2,8 +2,27 @@ package models
 
 import "time"
 
+const (
+	ReportCommand      = "report"
+	CheckTokensCommand = "check-tokens"
+	SprintCommand      = "sprint"
+	ConvertCommand     = "convert"
+
+	LastClosedSprint = "last"
+
+	DryRunFlag = "--dry-run"
+	StrictFlag = "--strict"
+)
+
 type InputArgs struct {
-	DateFrom  time.Time
-	DateTo    time.Time
-	AppConfig string
+	Command    string
+	DateFrom   time.Time
+	DateTo     time.Time
+	BoardId    int
+	Sprint     string // sprint id or "last" for the last closed sprint
+	AppConfig  string
+	SourceFile string // project config file to convert
+	TargetFile string // project config file to create by conversion
+	DryRun     bool   // fetch and reconcile data, but write nothing
+	Strict     bool   // refuse to create report if project config has issues
 }
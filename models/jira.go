@@ -0,0 +1,118 @@
+package models
+
+type JiraSearchRequest struct {
+	Jql           string   `json:"jql"`
+	Fields        []string `json:"fields"`
+	MaxResults    int      `json:"maxResults"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
+}
+
+type JiraSearchResponse struct {
+	Issues        []JiraIssue `json:"issues"`
+	NextPageToken string      `json:"nextPageToken"`
+	IsLast        bool        `json:"isLast"`
+}
+
+type JiraIssue struct {
+	Id     string          `json:"id"`
+	Key    string          `json:"key"`
+	Fields JiraIssueFields `json:"fields"`
+}
+
+type JiraIssueFields struct {
+	Summary   string        `json:"summary"`
+	IssueType JiraIssueType `json:"issuetype"`
+	Status    JiraStatus    `json:"status"`
+	Priority  *JiraPriority `json:"priority"`
+	Assignee  *JiraUser     `json:"assignee"`
+	Parent    *JiraParent   `json:"parent"`
+
+	TimeOriginalEstimate *int `json:"timeoriginalestimate"` // seconds
+	TimeEstimate         *int `json:"timeestimate"`         // remaining, seconds
+	TimeSpent            *int `json:"timespent"`            // total logged, seconds
+
+	Components []JiraComponent `json:"components"`
+	Labels     []string        `json:"labels"`
+
+	Project        JiraIssueProject `json:"project"`
+	ResolutionDate string           `json:"resolutiondate"`
+}
+
+type JiraComponent struct {
+	Name string `json:"name"`
+}
+
+type JiraIssueType struct {
+	Name           string `json:"name"`
+	HierarchyLevel int    `json:"hierarchyLevel"` // -1: sub-task, 0: standard issue, 1: epic
+}
+
+type JiraParent struct {
+	Key    string           `json:"key"`
+	Fields JiraParentFields `json:"fields"`
+}
+
+type JiraParentFields struct {
+	Summary   string        `json:"summary"`
+	IssueType JiraIssueType `json:"issuetype"`
+}
+
+type JiraStatus struct {
+	Name           string             `json:"name"`
+	StatusCategory JiraStatusCategory `json:"statusCategory"`
+}
+
+type JiraStatusCategory struct {
+	Key string `json:"key"` // new, indeterminate or done
+}
+
+type JiraIssueProject struct {
+	Key string `json:"key"`
+}
+
+type JiraPriority struct {
+	Name string `json:"name"`
+}
+
+type JiraUser struct {
+	AccountId    string `json:"accountId"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+type JiraProject struct {
+	Key  string    `json:"key"`
+	Name string    `json:"name"`
+	Lead *JiraUser `json:"lead"`
+}
+
+type JiraWorklogResponse struct {
+	StartAt    int           `json:"startAt"`
+	MaxResults int           `json:"maxResults"`
+	Total      int           `json:"total"`
+	Worklogs   []JiraWorklog `json:"worklogs"`
+}
+
+type JiraWorklog struct {
+	Id               string   `json:"id"`
+	Author           JiraUser `json:"author"`
+	Started          string   `json:"started"`
+	TimeSpentSeconds int      `json:"timeSpentSeconds"`
+}
+
+type JiraSprintsResponse struct {
+	MaxResults int          `json:"maxResults"`
+	StartAt    int          `json:"startAt"`
+	IsLast     bool         `json:"isLast"`
+	Values     []JiraSprint `json:"values"`
+}
+
+type JiraSprint struct {
+	Id            int    `json:"id"`
+	Name          string `json:"name"`
+	State         string `json:"state"`
+	StartDate     string `json:"startDate"`
+	EndDate       string `json:"endDate"`
+	CompleteDate  string `json:"completeDate"`
+	OriginBoardId int    `json:"originBoardId"`
+}
@@ -1,20 +1,248 @@
 package models
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	rateDateFormat = "2006-01-02"
+)
+
 type ProjectConfigWrapper struct {
-	ProjectConfigs []ProjectConfig
+	ProjectConfigs []ProjectConfig  `yaml:"projects,omitempty" json:"projects,omitempty"`
+	People         []UserConfig     `yaml:"people,omitempty" json:"people,omitempty"` // global registry with defaults for all projects
+	RateCards      []RateCardConfig `yaml:"rate_cards,omitempty" json:"rate_cards,omitempty"`
 }
 
 type ProjectConfig struct {
-	Key              string
-	DisplayName      string
-	Owner            string
-	Manager          string
-	UserNameToConfig map[string]UserConfig
+	Key         string       `yaml:"key" json:"key"`
+	DisplayName string       `yaml:"display_name,omitempty" json:"display_name,omitempty"`
+	Owner       string       `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Manager     string       `yaml:"manager,omitempty" json:"manager,omitempty"`
+	Users       []UserConfig `yaml:"users,omitempty" json:"users,omitempty"`
+
+	Inactive bool `yaml:"inactive,omitempty" json:"inactive,omitempty"` // project was not part of the last run
 }
 
 type UserConfig struct {
-	Position string
-	Rate     int
+	AccountId string  `yaml:"account_id,omitempty" json:"account_id,omitempty"`
+	Email     string  `yaml:"email,omitempty" json:"email,omitempty"`
+	Name      string  `yaml:"name" json:"name"` // cosmetic, used for matching only if account id is unknown
+	Position  string  `yaml:"position,omitempty" json:"position,omitempty"`
+	Rate      float64 `yaml:"rate,omitempty" json:"rate,omitempty"`         // base rate, used before the first effective date of rate history
+	Currency  string  `yaml:"currency,omitempty" json:"currency,omitempty"` // empty for default (dollar)
+
+	RateHistory RateHistory `yaml:"rate_history,omitempty" json:"rate_history,omitempty"`
+
+	LineManager string `yaml:"line_manager,omitempty" json:"line_manager,omitempty"` // people registry only
+	Team        string `yaml:"team,omitempty" json:"team,omitempty"`                 // people registry only
+
+	Active   bool   `yaml:"active,omitempty" json:"active,omitempty"`       // project sheets only, user has worklogs in the last run
+	LastSeen string `yaml:"last_seen,omitempty" json:"last_seen,omitempty"` // project sheets only, yyyy-mm-dd of the latest worklog
+	Archived bool   `yaml:"archived,omitempty" json:"archived,omitempty"`   // project sheets only, user is listed in archived section
+}
+
+// WithDefaults fills empty values with defaults (e.g. from people registry), rate, rate history and currency
+// are taken together unless rate or rate history is set, so that the rate is never paired with another currency.
+func (s *UserConfig) WithDefaults(defaults *UserConfig) UserConfig {
+	result := *s
+	if defaults == nil {
+		return result
+	}
+
+	if len(result.Email) == 0 {
+		result.Email = defaults.Email
+	}
+	if len(result.Position) == 0 {
+		result.Position = defaults.Position
+	}
+	if result.Rate == 0 && len(result.RateHistory) == 0 {
+		result.Rate = defaults.Rate
+		result.RateHistory = defaults.RateHistory
+		result.Currency = defaults.Currency
+	}
+	if len(result.LineManager) == 0 {
+		result.LineManager = defaults.LineManager
+	}
+	if len(result.Team) == 0 {
+		result.Team = defaults.Team
+	}
+
+	return result
+}
+
+// GetRateConfigs lists rates to be written as user rows, base rate row is omitted if rate is defined by history only.
+func (s *UserConfig) GetRateConfigs() []RateConfig {
+	var rateConfigs []RateConfig
+	if len(s.RateHistory) == 0 || s.Rate != 0 {
+		rateConfigs = append(rateConfigs, RateConfig{Rate: s.Rate})
+	}
+	return append(rateConfigs, s.RateHistory...)
+}
+
+// ProjectConfigChange describes a change of project config file made by synchronization.
+type ProjectConfigChange struct {
+	Sheet   string
+	Subject string // project or user name
+	Change  string
+	Details string
+}
+
+// ProjectConfigIssue describes a problem of project config file found by validation.
+type ProjectConfigIssue struct {
+	Sheet   string
+	Subject string // project, user name, position or cell
+	Issue   string
+	Details string
+}
+
+// RateCardConfig defines rate by position, optionally limited to project or client (project owner).
+type RateCardConfig struct {
+	Position string  `yaml:"position" json:"position"`
+	Rate     float64 `yaml:"rate" json:"rate"`
+	Currency string  `yaml:"currency,omitempty" json:"currency,omitempty"`
+	Project  string  `yaml:"project,omitempty" json:"project,omitempty"`
+	Client   string  `yaml:"client,omitempty" json:"client,omitempty"`
+}
+
+type RateConfig struct {
+	EffectiveFrom string  `yaml:"effective_from,omitempty" json:"effective_from,omitempty"` // yyyy-mm-dd
+	Rate          float64 `yaml:"rate" json:"rate"`
+}
+
+// RateHistory is a list of effective-dated rates sorted by effective date.
+type RateHistory []RateConfig
+
+// Get finds rate effective on the date (yyyy-mm-dd), base rate is used before the first effective date.
+func (s RateHistory) Get(baseRate float64, date string) float64 {
+	rate := baseRate
+	for _, rateConfig := range s {
+		if rateConfig.EffectiveFrom > date {
+			break
+		}
+		rate = rateConfig.Rate
+	}
+	return rate
+}
+
+// SortRateHistories checks effective dates (yyyy-mm-dd) of every user and sorts rate histories by them,
+// so that hand-edited files (e.g. yaml) get the same order as the one built by AddUserRow.
+func (s *ProjectConfigWrapper) SortRateHistories() error {
+	for i := range s.ProjectConfigs {
+		for j := range s.ProjectConfigs[i].Users {
+			err := s.ProjectConfigs[i].Users[j].sortRateHistory(s.ProjectConfigs[i].Key)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for i := range s.People {
+		err := s.People[i].sortRateHistory("People")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *UserConfig) sortRateHistory(sheet string) error {
+	for _, rateConfig := range s.RateHistory {
+		if _, err := time.Parse(rateDateFormat, rateConfig.EffectiveFrom); err != nil {
+			return fmt.Errorf("error: effective date of %s in %s project config is not recognized: %s", s.Name, sheet, rateConfig.EffectiveFrom)
+		}
+	}
+	sort.SliceStable(s.RateHistory, func(i, j int) bool {
+		return s.RateHistory[i].EffectiveFrom < s.RateHistory[j].EffectiveFrom
+	})
+	return nil
+}
+
+// GetUser finds user by account id, falls back to name for users
+// which do not have account id yet (e.g. from sheets created before account ids were stored).
+func (s *ProjectConfig) GetUser(accountId, name string) *UserConfig {
+	return findUserConfig(s.Users, accountId, name)
+}
+
+// GetPerson finds person in people registry the same way as project user.
+func (s *ProjectConfigWrapper) GetPerson(accountId, name string) *UserConfig {
+	return findUserConfig(s.People, accountId, name)
+}
+
+// GetRateCard finds the most specific rate card for position: project one first, then client one, then general one.
+func (s *ProjectConfigWrapper) GetRateCard(position, projectKey, client string) *RateCardConfig {
+	var clientRateCard, generalRateCard *RateCardConfig
+
+	for i := range s.RateCards {
+		rateCard := &s.RateCards[i]
+		if !strings.EqualFold(strings.TrimSpace(rateCard.Position), strings.TrimSpace(position)) {
+			continue
+		}
+
+		switch {
+		case len(rateCard.Project) > 0:
+			if rateCard.Project == projectKey {
+				return rateCard
+			}
+		case len(rateCard.Client) > 0:
+			if clientRateCard == nil && strings.EqualFold(rateCard.Client, client) {
+				clientRateCard = rateCard
+			}
+		default:
+			if generalRateCard == nil {
+				generalRateCard = rateCard
+			}
+		}
+	}
+
+	if clientRateCard != nil {
+		return clientRateCard
+	}
+	return generalRateCard
+}
+
+// AddUserRow adds user read from a table row (e.g. sheet or csv), additional rows of the same user
+// make rate history: rate of the row is added to history if effective date is set, otherwise it is the base rate.
+func (s *ProjectConfig) AddUserRow(userConfig UserConfig, rateConfig RateConfig) {
+	var existingUserConfig *UserConfig
+	for i := range s.Users {
+		if len(userConfig.AccountId) > 0 && s.Users[i].AccountId == userConfig.AccountId ||
+			len(userConfig.AccountId) == 0 && len(s.Users[i].AccountId) == 0 && s.Users[i].Name == userConfig.Name {
+			existingUserConfig = &s.Users[i]
+			break
+		}
+	}
+	if existingUserConfig == nil {
+		s.Users = append(s.Users, userConfig)
+		existingUserConfig = &s.Users[len(s.Users)-1]
+	}
+
+	if len(rateConfig.EffectiveFrom) == 0 {
+		existingUserConfig.Rate = rateConfig.Rate
+		return
+	}
+
+	rateHistory := append(existingUserConfig.RateHistory, rateConfig)
+	sort.SliceStable(rateHistory, func(i, j int) bool {
+		return rateHistory[i].EffectiveFrom < rateHistory[j].EffectiveFrom
+	})
+	existingUserConfig.RateHistory = rateHistory
+}
+
+func findUserConfig(users []UserConfig, accountId, name string) *UserConfig {
+	for i := range users {
+		if len(accountId) > 0 && users[i].AccountId == accountId {
+			return &users[i]
+		}
+	}
+	for i := range users {
+		if len(users[i].AccountId) == 0 && users[i].Name == name {
+			return &users[i]
+		}
+	}
+	return nil
 }
 
 func (s *ProjectConfigWrapper) Get(projectKey string) *ProjectConfig {
@@ -51,7 +279,15 @@ type InfoProjectConfigContext struct {
 type UserProjectConfigContext struct {
 	HeaderRowIndex int
 
-	NameColumn     string
-	PositionColumn string
-	RateColumn     string
+	NameColumn          string
+	PositionColumn      string
+	RateColumn          string
+	AccountIdColumn     string
+	EmailColumn         string
+	CurrencyColumn      string
+	EffectiveFromColumn string
+	LineManagerColumn   string // people registry only
+	TeamColumn          string // people registry only
+	ActiveColumn        string // project sheets only
+	LastSeenColumn      string // project sheets only
 }
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRateHistoryGet(t *testing.T) {
+	rateHistory := RateHistory{
+		{EffectiveFrom: "2026-03-01", Rate: 40},
+		{EffectiveFrom: "2026-06-15", Rate: 45},
+	}
+
+	tests := []struct {
+		name        string
+		rateHistory RateHistory
+		date        string
+		want        float64
+	}{
+		{name: "before the first effective date", rateHistory: rateHistory, date: "2026-02-28", want: 30},
+		{name: "on the first effective date", rateHistory: rateHistory, date: "2026-03-01", want: 40},
+		{name: "between effective dates", rateHistory: rateHistory, date: "2026-06-14", want: 40},
+		{name: "on the last effective date", rateHistory: rateHistory, date: "2026-06-15", want: 45},
+		{name: "after the last effective date", rateHistory: rateHistory, date: "2026-12-31", want: 45},
+		{name: "empty history", rateHistory: nil, date: "2026-06-15", want: 30},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.rateHistory.Get(30, test.date); got != test.want {
+				t.Fatalf("Get(30, %s) = %v, want %v", test.date, got, test.want)
+			}
+		})
+	}
+}
+
+func TestProjectConfigWrapperGetRateCard(t *testing.T) {
+	wrapper := &ProjectConfigWrapper{RateCards: []RateCardConfig{
+		{Position: "Developer", Rate: 30},
+		{Position: "Developer", Rate: 35, Client: "ClientCo"},
+		{Position: "Developer", Rate: 40, Project: "ABC"},
+		{Position: "QA", Rate: 25, Client: "ClientCo"},
+		{Position: "Designer", Rate: 20},
+		{Position: "Designer", Rate: 22},
+	}}
+
+	tests := []struct {
+		name       string
+		position   string
+		projectKey string
+		client     string
+		want       float64
+		wantNil    bool
+	}{
+		{name: "project card", position: "Developer", projectKey: "ABC", client: "ClientCo", want: 40},
+		{name: "client card", position: "Developer", projectKey: "XYZ", client: "ClientCo", want: 35},
+		{name: "client is matched case-insensitively", position: "Developer", projectKey: "XYZ", client: "clientco", want: 35},
+		{name: "general card", position: "Developer", projectKey: "XYZ", client: "OtherCo", want: 30},
+		{name: "position is matched case-insensitively", position: " developer ", projectKey: "XYZ", want: 30},
+		{name: "first general card", position: "Designer", projectKey: "ABC", want: 20},
+		{name: "client card of another client", position: "QA", projectKey: "ABC", client: "OtherCo", wantNil: true},
+		{name: "no card for position", position: "Manager", projectKey: "ABC", client: "ClientCo", wantNil: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rateCard := wrapper.GetRateCard(test.position, test.projectKey, test.client)
+			if test.wantNil {
+				if rateCard != nil {
+					t.Fatalf("GetRateCard() = %+v, want nil", rateCard)
+				}
+				return
+			}
+			if rateCard == nil || rateCard.Rate != test.want {
+				t.Fatalf("GetRateCard() = %+v, want rate %v", rateCard, test.want)
+			}
+		})
+	}
+}
+
+func TestUserConfigWithDefaults(t *testing.T) {
+	person := &UserConfig{Email: "alice@example.com", Position: "Developer", Rate: 40, Currency: "EUR",
+		RateHistory: RateHistory{{EffectiveFrom: "2026-06-01", Rate: 45}}, LineManager: "Max Lead", Team: "Core"}
+
+	tests := []struct {
+		name     string
+		user     UserConfig
+		defaults *UserConfig
+		want     UserConfig
+	}{
+		{
+			name: "no defaults",
+			user: UserConfig{Name: "Alice", Rate: 30},
+			want: UserConfig{Name: "Alice", Rate: 30},
+		},
+		{
+			name:     "empty values are taken from defaults",
+			user:     UserConfig{Name: "Alice"},
+			defaults: person,
+			want: UserConfig{Name: "Alice", Email: "alice@example.com", Position: "Developer", Rate: 40, Currency: "EUR",
+				RateHistory: RateHistory{{EffectiveFrom: "2026-06-01", Rate: 45}}, LineManager: "Max Lead", Team: "Core"},
+		},
+		{
+			name:     "typed values keep precedence",
+			user:     UserConfig{Name: "Alice", Email: "a@example.com", Position: "Lead", Rate: 50, Currency: "USD", LineManager: "Ann", Team: "Web"},
+			defaults: person,
+			want:     UserConfig{Name: "Alice", Email: "a@example.com", Position: "Lead", Rate: 50, Currency: "USD", LineManager: "Ann", Team: "Web"},
+		},
+		{
+			name:     "own rate is not paired with default currency",
+			user:     UserConfig{Name: "Alice", Rate: 50},
+			defaults: person,
+			want: UserConfig{Name: "Alice", Email: "alice@example.com", Position: "Developer", Rate: 50,
+				LineManager: "Max Lead", Team: "Core"},
+		},
+		{
+			name:     "own rate history is not mixed with default rate",
+			user:     UserConfig{Name: "Alice", RateHistory: RateHistory{{EffectiveFrom: "2026-03-01", Rate: 35}}},
+			defaults: person,
+			want: UserConfig{Name: "Alice", Email: "alice@example.com", Position: "Developer",
+				RateHistory: RateHistory{{EffectiveFrom: "2026-03-01", Rate: 35}}, LineManager: "Max Lead", Team: "Core"},
+		},
+		{
+			name:     "zero rate takes default rate with history and currency",
+			user:     UserConfig{Name: "Alice", Currency: "USD"},
+			defaults: person,
+			want: UserConfig{Name: "Alice", Email: "alice@example.com", Position: "Developer", Rate: 40, Currency: "EUR",
+				RateHistory: RateHistory{{EffectiveFrom: "2026-06-01", Rate: 45}}, LineManager: "Max Lead", Team: "Core"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.user.WithDefaults(test.defaults); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("WithDefaults() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
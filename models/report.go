@@ -3,9 +3,14 @@ package models
 import "time"
 
 type Report struct {
-	DateFrom time.Time
-	DateTo   time.Time
-	Projects []Project
+	DateFrom   time.Time
+	DateTo     time.Time
+	Projects   []Project
+	Epics      []ProjectEpics
+	Variances  []IssueVariance
+	Allocation *Allocation
+	Sprint     *Sprint
+	Violations []HygieneViolation
 }
 
 type Project struct {
@@ -18,14 +23,83 @@ type Project struct {
 
 type User struct {
 	AccountId string
+	Email     string
 	Name      string
 	Position  string
-	Rate      int
+	Rate      float64 // blended over the period if rate changed in it
+	Currency  string
 	Issues    []Issue
+
+	RateFromCard bool // rate is not set for user and taken from rate card by position
+
+	LineManager string
+	Team        string
+
+	BaseRate    float64
+	RateHistory RateHistory
+}
+
+// GetRate finds rate effective on the date (yyyy-mm-dd).
+func (s *User) GetRate(date string) float64 {
+	return s.RateHistory.Get(s.BaseRate, date)
+}
+
+// GetCost computes cost of efforts using rate effective on the day of each effort.
+func (s *User) GetCost(efforts []Effort) float64 {
+	cost := 0.0
+	for _, effort := range efforts {
+		cost += float64(effort.TimeSpentSeconds) / 3600 * s.GetRate(effort.Date)
+	}
+	return cost
+}
+
+// GetLastEffortDate finds date (yyyy-mm-dd) of the latest effort, empty if there are no efforts.
+func (s *User) GetLastEffortDate() string {
+	lastDate := ""
+	for _, issue := range s.Issues {
+		for _, effort := range issue.Efforts {
+			if effort.Date > lastDate {
+				lastDate = effort.Date
+			}
+		}
+	}
+	return lastDate
+}
+
+// GetBlendedRate computes average rate of efforts weighted by time spent,
+// base rate is returned if there are no efforts.
+func (s *User) GetBlendedRate(efforts []Effort) float64 {
+	seconds := 0
+	for _, effort := range efforts {
+		seconds += effort.TimeSpentSeconds
+	}
+	if seconds == 0 {
+		return s.BaseRate
+	}
+	return s.GetCost(efforts) / (float64(seconds) / 3600)
 }
 
 type Issue struct {
-	Key     string
+	Key        string
+	ProjectKey string // actual project of the issue in Jira
+	Summary    string
+	Type       string
+	Status     string
+	Priority   string
+	Assignee   string
+	EpicKey    string
+	Epic       string
+
+	Closed         bool   // status is in done category
+	ResolutionDate string // yyyy-mm-dd
+
+	OriginalEstimateSeconds  int
+	RemainingEstimateSeconds int
+	TotalSpentSeconds        int // logged for all time by all users
+
+	Components []string
+	Labels     []string
+
 	Efforts []Effort
 }
 
@@ -33,3 +107,86 @@ type Effort struct {
 	Date             string
 	TimeSpentSeconds int
 }
+
+type ProjectEpics struct {
+	ProjectKey string
+	Epics      []Epic
+}
+
+type Epic struct {
+	Key             string // empty for issues without epic
+	Summary         string
+	Hours           float64
+	Cost            float64 // zero if users have different currencies
+	Currency        string
+	MixedCurrencies bool // costs of users in different currencies are not summed up
+	Users           []EpicUser
+}
+
+type EpicUser struct {
+	Name     string
+	Rate     float64
+	Currency string
+	Hours    float64
+	Cost     float64
+}
+
+type Sprint struct {
+	Id       int
+	Name     string
+	DateFrom time.Time
+	DateTo   time.Time
+	Users    []SprintUser
+}
+
+type SprintUser struct {
+	ProjectKey  string
+	Name        string
+	Rate        float64
+	Currency    string
+	SprintHours float64 // logged on issues of the sprint
+	OtherHours  float64 // logged on other issues in the sprint period
+	Cost        float64 // cost of sprint hours
+}
+
+type IssueVariance struct {
+	ProjectKey     string
+	Key            string
+	Summary        string
+	EstimateHours  float64
+	RemainingHours float64
+	PeriodHours    float64 // logged in the report period
+	TotalHours     float64 // logged for all time
+	Overrun        float64 // percent of estimate exceeded by total hours
+}
+
+type Allocation struct {
+	Categories []string
+	Users      []AllocationUser
+}
+
+type AllocationUser struct {
+	AccountId string
+	Name      string
+	Currency  string
+	Hours     []float64 // per category, aligned with Allocation.Categories
+	Costs     []float64 // per category, aligned with Allocation.Categories
+}
+
+func (s *AllocationUser) GetTotalCost() float64 {
+	total := 0.0
+	for _, cost := range s.Costs {
+		total += cost
+	}
+	return total
+}
+
+type HygieneViolation struct {
+	ProjectKey string
+	UserName   string
+	Rule       string
+	IssueKey   string
+	Summary    string
+	Date       string
+	Hours      float64
+}
@@ -0,0 +1,26 @@
+package models
+
+type TokenCheck struct {
+	Token    string
+	Valid    bool
+	Error    string
+	Projects []ProjectTokenCheck
+}
+
+type ProjectTokenCheck struct {
+	Key      string
+	Readable bool
+	Error    string
+}
+
+func (s *TokenCheck) HasProblems() bool {
+	if !s.Valid {
+		return true
+	}
+	for _, project := range s.Projects {
+		if !project.Readable {
+			return true
+		}
+	}
+	return false
+}
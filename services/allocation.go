@@ -0,0 +1,165 @@
+package services
+
+import (
+	"log"
+	"math"
+	"pm-report/models"
+	"pm-report/utils"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultAllocationCategory = "Unallocated"
+)
+
+type AllocationService struct {
+	allocationAppConfig models.AllocationAppConfig
+}
+
+func NewAllocationService(allocationAppConfig models.AllocationAppConfig) *AllocationService {
+	return &AllocationService{allocationAppConfig: allocationAppConfig}
+}
+
+func (s *AllocationService) IsEnabled() bool {
+	return len(s.allocationAppConfig.Rules) > 0
+}
+
+// Fill allocates hours and cost of each user to cost categories by issue components and labels,
+// time of issue matching several categories is split equally between them.
+func (s *AllocationService) Fill(report *models.Report) {
+	categories := s.getCategories()
+	categoryToIndex := map[string]int{}
+	for i, category := range categories {
+		categoryToIndex[category] = i
+	}
+
+	keyToUser := map[string]*models.AllocationUser{}
+	unresolvedIssues := map[string]bool{}
+
+	for _, project := range report.Projects {
+		for _, user := range project.Users {
+			// costs in different currencies are not summed up, user gets a row per currency
+			key := s.getUserKey(user) + "|" + strings.ToUpper(strings.TrimSpace(user.Currency))
+			allocationUser, ok := keyToUser[key]
+			if !ok {
+				allocationUser = &models.AllocationUser{
+					AccountId: user.AccountId,
+					Name:      user.Name,
+					Currency:  user.Currency,
+					Hours:     make([]float64, len(categories)),
+					Costs:     make([]float64, len(categories)),
+				}
+				keyToUser[key] = allocationUser
+			}
+
+			for _, issue := range user.Issues {
+				if len(issue.Type) == 0 {
+					unresolvedIssues[issue.Key] = true // not resolved in Jira, it has no components and labels
+				}
+
+				seconds := 0
+				for _, effort := range issue.Efforts {
+					seconds += effort.TimeSpentSeconds
+				}
+
+				issueCategories := s.getIssueCategories(issue)
+				share := utils.ToHours(seconds) / float64(len(issueCategories))
+				costShare := user.GetCost(issue.Efforts) / float64(len(issueCategories))
+
+				for _, category := range issueCategories {
+					index := categoryToIndex[category]
+					allocationUser.Hours[index] += share
+					allocationUser.Costs[index] += costShare
+				}
+			}
+		}
+	}
+
+	users := make([]models.AllocationUser, 0, len(keyToUser))
+	for _, allocationUser := range keyToUser {
+		for i := range categories {
+			allocationUser.Hours[i] = math.Round(allocationUser.Hours[i]*100) / 100
+			allocationUser.Costs[i] = math.Round(allocationUser.Costs[i]*100) / 100
+		}
+		users = append(users, *allocationUser)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !strings.EqualFold(users[i].Name, users[j].Name) {
+			return strings.ToLower(users[i].Name) < strings.ToLower(users[j].Name)
+		}
+		if users[i].AccountId != users[j].AccountId {
+			return users[i].AccountId < users[j].AccountId
+		}
+		return users[i].Currency < users[j].Currency
+	})
+
+	report.Allocation = &models.Allocation{
+		Categories: categories,
+		Users:      users,
+	}
+
+	log.Println("Allocated costs to categories:", strings.Join(categories, ", "))
+	if len(unresolvedIssues) > 0 {
+		log.Println("Warning: issues are not resolved in Jira, their costs are allocated to", s.getDefaultCategory(), "category:", len(unresolvedIssues))
+	}
+}
+
+// getUserKey identifies user across projects by account id, name is used only for users without account id.
+func (s *AllocationService) getUserKey(user models.User) string {
+	if len(user.AccountId) > 0 {
+		return "id:" + user.AccountId
+	}
+	return "name:" + user.Name
+}
+
+func (s *AllocationService) getCategories() []string {
+	var categories []string
+	for _, rule := range s.allocationAppConfig.Rules {
+		if !utils.Contains(categories, rule.Category) {
+			categories = append(categories, rule.Category)
+		}
+	}
+
+	defaultCategory := s.getDefaultCategory()
+	if !utils.Contains(categories, defaultCategory) {
+		categories = append(categories, defaultCategory)
+	}
+
+	return categories
+}
+
+func (s *AllocationService) getDefaultCategory() string {
+	if len(s.allocationAppConfig.Default) > 0 {
+		return s.allocationAppConfig.Default
+	}
+	return defaultAllocationCategory
+}
+
+func (s *AllocationService) getIssueCategories(issue models.Issue) []string {
+	var categories []string
+
+	for _, rule := range s.allocationAppConfig.Rules {
+		if utils.Contains(categories, rule.Category) {
+			continue
+		}
+
+		matched := false
+		for _, component := range utils.ToList(rule.Components) {
+			matched = matched || utils.Contains(issue.Components, component)
+		}
+		for _, label := range utils.ToList(rule.Labels) {
+			matched = matched || utils.Contains(issue.Labels, label)
+		}
+
+		if matched {
+			categories = append(categories, rule.Category)
+		}
+	}
+
+	if len(categories) == 0 {
+		categories = append(categories, s.getDefaultCategory())
+	}
+
+	return categories
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"pm-report/models"
+	"reflect"
+	"testing"
+)
+
+func TestAllocationFillGroupsUsersByAccountId(t *testing.T) {
+	report := &models.Report{Projects: []models.Project{
+		{Key: "ABC", Users: []models.User{
+			newTestUser("a1", "Alex", "", 10, 1),
+			newTestUser("a2", "Alex", "", 10, 2),
+			newTestUser("", "Sam", "", 10, 3),
+		}},
+		{Key: "XYZ", Users: []models.User{
+			newTestUser("a1", "Alex Renamed", "", 10, 4),
+			newTestUser("", "Sam", "", 10, 5),
+		}},
+	}}
+
+	allocationService := NewAllocationService(models.AllocationAppConfig{
+		Rules: []models.RuleAllocationAppConfig{{Category: "Frontend", Components: "Frontend"}},
+	})
+	allocationService.Fill(report)
+
+	var got []models.AllocationUser
+	for _, user := range report.Allocation.Users {
+		got = append(got, models.AllocationUser{AccountId: user.AccountId, Name: user.Name, Hours: user.Hours, Costs: user.Costs})
+	}
+	want := []models.AllocationUser{
+		{AccountId: "a1", Name: "Alex", Hours: []float64{0, 5}, Costs: []float64{0, 50}},
+		{AccountId: "a2", Name: "Alex", Hours: []float64{0, 2}, Costs: []float64{0, 20}},
+		{AccountId: "", Name: "Sam", Hours: []float64{0, 8}, Costs: []float64{0, 80}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("users = %+v, want %+v", got, want)
+	}
+}
+
+func TestAllocationFillSplitsUserByCurrency(t *testing.T) {
+	alice := newTestUser("a1", "Alice", "EUR", 50, 2)
+	aliceInUsd := newTestUser("a1", "Alice", "USD", 40, 1)
+	report := &models.Report{Projects: []models.Project{
+		{Key: "ABC", Users: []models.User{alice}},
+		{Key: "XYZ", Users: []models.User{aliceInUsd}},
+	}}
+
+	NewAllocationService(models.AllocationAppConfig{
+		Rules: []models.RuleAllocationAppConfig{{Category: "Frontend", Components: "Frontend"}},
+	}).Fill(report)
+
+	users := report.Allocation.Users
+	if len(users) != 2 {
+		t.Fatalf("users = %+v, want a row per currency", users)
+	}
+	if users[0].Currency != "EUR" || users[0].GetTotalCost() != 100 || users[1].Currency != "USD" || users[1].GetTotalCost() != 40 {
+		t.Fatalf("users = %+v, want EUR 100 and USD 40", users)
+	}
+}
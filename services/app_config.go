@@ -1,10 +1,13 @@
 package services
 
 import (
+	"errors"
+	"fmt"
 	"github.com/spf13/viper"
 	"log"
 	"pm-report/models"
 	"pm-report/utils"
+	"strings"
 )
 
 type AppConfigService struct {
@@ -33,5 +36,46 @@ func (s *AppConfigService) Get() (*models.AppConfig, error) {
 
 	log.Println("Parsed", s.filePath, utils.ToPrettyString("config", appConfig))
 
+	err = s.validate(&appConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	return &appConfig, nil
 }
+
+// validate rejects unknown values of enum-style settings and settings which would be silently ignored.
+func (s *AppConfigService) validate(appConfig *models.AppConfig) error {
+	switch appConfig.Tempo.Strategy {
+	case "", models.ProjectFetchStrategy, models.OrganizationFetchStrategy:
+	default:
+		return fmt.Errorf("error: tempo.strategy is not recognized: %s (expected %s or %s)",
+			appConfig.Tempo.Strategy, models.ProjectFetchStrategy, models.OrganizationFetchStrategy)
+	}
+
+	switch appConfig.Tempo.Discovery.Mode {
+	case "", models.OffDiscoveryMode, models.WarnDiscoveryMode, models.IncludeDiscoveryMode:
+	default:
+		return fmt.Errorf("error: tempo.discovery.mode is not recognized: %s (expected %s, %s or %s)",
+			appConfig.Tempo.Discovery.Mode, models.OffDiscoveryMode, models.WarnDiscoveryMode, models.IncludeDiscoveryMode)
+	}
+
+	for _, token := range appConfig.Tempo.Tokens {
+		switch token.Source {
+		case "", models.TempoWorklogSource, models.JiraWorklogSource:
+		default:
+			return fmt.Errorf("error: source of token for %s projects is not recognized: %s (expected %s or %s)",
+				token.Projects, token.Source, models.TempoWorklogSource, models.JiraWorklogSource)
+		}
+	}
+
+	if len(appConfig.Jira.Url) == 0 {
+		for _, filter := range appConfig.Jira.Filters {
+			if len(strings.TrimSpace(filter.Jql)) > 0 {
+				return errors.New("error: jira.filters require jira.url, otherwise worklogs are counted unfiltered")
+			}
+		}
+	}
+
+	return nil
+}
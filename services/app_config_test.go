@@ -0,0 +1,36 @@
+package services
+
+import (
+	"pm-report/models"
+	"testing"
+)
+
+func TestAppConfigServiceValidate(t *testing.T) {
+	filters := []models.FilterJiraAppConfig{{Project: "ABC", Jql: "labels is EMPTY"}}
+
+	tests := []struct {
+		name      string
+		appConfig models.AppConfig
+		wantErr   bool
+	}{
+		{name: "filters with jira", appConfig: models.AppConfig{Jira: models.JiraAppConfig{Url: "https://example.atlassian.net", Filters: filters}}},
+		{name: "filters without jira", appConfig: models.AppConfig{Jira: models.JiraAppConfig{Filters: filters}}, wantErr: true},
+		{name: "empty filter without jira", appConfig: models.AppConfig{Jira: models.JiraAppConfig{Filters: []models.FilterJiraAppConfig{{Project: "ABC"}}}}},
+		{name: "default strategy", appConfig: models.AppConfig{}},
+		{name: "organization strategy", appConfig: models.AppConfig{Tempo: models.TempoAppConfig{Strategy: "organization"}}},
+		{name: "misspelled strategy", appConfig: models.AppConfig{Tempo: models.TempoAppConfig{Strategy: "organisation"}}, wantErr: true},
+		{name: "include discovery mode", appConfig: models.AppConfig{Tempo: models.TempoAppConfig{Discovery: models.DiscoveryTempoAppConfig{Mode: "include"}}}},
+		{name: "unknown discovery mode", appConfig: models.AppConfig{Tempo: models.TempoAppConfig{Discovery: models.DiscoveryTempoAppConfig{Mode: "false"}}}, wantErr: true},
+		{name: "jira token source", appConfig: models.AppConfig{Tempo: models.TempoAppConfig{Tokens: []models.TokenTempoAppConfig{{Source: "jira"}}}}},
+		{name: "unknown token source", appConfig: models.AppConfig{Tempo: models.TempoAppConfig{Tokens: []models.TokenTempoAppConfig{{Source: "Jira"}}}}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := NewAppConfigService("").validate(&test.appConfig)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("validate() = %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
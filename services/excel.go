@@ -9,6 +9,7 @@ import (
 	"math/big"
 	"os"
 	"pm-report/models"
+	"pm-report/utils"
 	"strconv"
 	"strings"
 	"time"
@@ -51,6 +52,41 @@ func (s *ExcelService) Save(report *models.Report) error {
 		return err
 	}
 
+	if len(report.Epics) > 0 {
+		err = s.fillEpicsSheet(f, report)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(report.Variances) > 0 {
+		err = s.fillVarianceSheet(f, report)
+		if err != nil {
+			return err
+		}
+	}
+
+	if report.Allocation != nil {
+		err = s.fillAllocationSheet(f, report)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(report.Violations) > 0 {
+		err = s.fillHygieneSheet(f, report)
+		if err != nil {
+			return err
+		}
+	}
+
+	if report.Sprint != nil {
+		err = s.fillSprintSheet(f, report)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = f.SaveAs(s.filePath)
 	if err != nil {
 		return err
@@ -81,6 +117,15 @@ func (s *ExcelService) createOrOpenFile() (*excelize.File, error) {
 }
 
 func (s *ExcelService) getSheetName(report *models.Report) string {
+	if report.Sprint != nil {
+		// leave room for suffixes of additional sheets, sheet name is limited to 31 chars
+		name := []rune(invalidSheetNameChars.ReplaceAllString(report.Sprint.Name, " "))
+		if len(name) > 22 {
+			name = name[:22]
+		}
+		return string(name)
+	}
+
 	dateFrom := report.DateFrom
 	dateTo := report.DateTo
 
@@ -154,6 +199,44 @@ func (s *ExcelService) createSheet(f *excelize.File, sheet string) error {
 	return nil
 }
 
+// createTableSheet (re)creates additional sheet with a plain table header.
+func (s *ExcelService) createTableSheet(f *excelize.File, sheet string, titles []string, widths []float64) error {
+	if f.GetSheetIndex(sheet) != -1 {
+		f.DeleteSheet(sheet)
+	}
+	f.NewSheet(sheet)
+
+	for i, title := range titles {
+		col, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return err
+		}
+
+		err = f.SetCellValue(sheet, col+"1", title)
+		if err != nil {
+			return err
+		}
+
+		err = f.SetColWidth(sheet, col, col, widths[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	alignment := excelize.Alignment{Horizontal: "center"}
+	font := excelize.Font{Bold: true}
+	style, err := f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &font})
+	if err != nil {
+		return err
+	}
+	err = f.SetRowStyle(sheet, 1, 1, style)
+	if err != nil {
+		return err
+	}
+
+	return f.SetPanes(sheet, `{"freeze": true, "y_split": 1, "top_left_cell": "A2", "active_pane": "bottomLeft"}`)
+}
+
 func (s *ExcelService) fillHeader(f *excelize.File, sheet string, context *models.ExcelContext, report *models.Report) error {
 	rowIndex := strconv.Itoa(context.LastRowIndex)
 
@@ -371,7 +454,9 @@ func (s *ExcelService) fillProjectRow(f *excelize.File, sheet string, project *m
 	if err != nil {
 		return err
 	}
-	totalCostStyle, err := f.NewStyle(&excelize.Style{Font: &font, NumFmt: 177})
+	currencies, costs := s.getProjectCosts(project)
+	currency, _ := s.getCommonCurrency(currencies)
+	totalCostStyle, err := f.NewStyle(&excelize.Style{Font: &font, NumFmt: 177, CustomNumFmt: utils.ToCurrencyNumFmt(currency)})
 	if err != nil {
 		return err
 	}
@@ -394,7 +479,7 @@ func (s *ExcelService) fillProjectRow(f *excelize.File, sheet string, project *m
 		if err != nil {
 			return err
 		}
-		totalCostStyle, err = f.NewStyle(&excelize.Style{Border: borders, Font: &font, Fill: fill, NumFmt: 177})
+		totalCostStyle, err = f.NewStyle(&excelize.Style{Border: borders, Font: &font, Fill: fill, NumFmt: 177, CustomNumFmt: utils.ToCurrencyNumFmt(currency)})
 		if err != nil {
 			return err
 		}
@@ -465,7 +550,7 @@ func (s *ExcelService) fillProjectRow(f *excelize.File, sheet string, project *m
 	}
 
 	formula = "sum(" + context.TotalCostColumn + firstRowIndex + ":" + context.TotalCostColumn + lastRowIndex + ")"
-	err = f.SetCellFormula(sheet, context.TotalCostColumn+rowIndex, formula)
+	err = s.setCostTotal(f, sheet, context.TotalCostColumn+rowIndex, formula, project.Key+" project", currencies, costs)
 	if err != nil {
 		return err
 	}
@@ -532,9 +617,134 @@ func (s *ExcelService) prepareUserRows(f *excelize.File, sheet string, project *
 		return err
 	}
 
+	// users with own currency or rate from rate card (shown in italic), rows are filled in the same order
+	for i, user := range project.Users {
+		if len(user.Currency) == 0 && !user.RateFromCard {
+			continue
+		}
+
+		userRowIndex := strconv.Itoa(context.LastRowIndex + 1 + i)
+
+		style, err = f.NewStyle(&excelize.Style{Border: borders, Fill: fill, NumFmt: 177, CustomNumFmt: utils.ToCurrencyNumFmt(user.Currency)})
+		if err != nil {
+			return err
+		}
+		err = f.SetCellStyle(sheet, context.TotalCostColumn+userRowIndex, context.TotalCostColumn+userRowIndex, style)
+		if err != nil {
+			return err
+		}
+
+		if user.RateFromCard {
+			style, err = f.NewStyle(&excelize.Style{Border: borders, Fill: fill, Font: &excelize.Font{Italic: true}, NumFmt: 177, CustomNumFmt: utils.ToCurrencyNumFmt(user.Currency)})
+			if err != nil {
+				return err
+			}
+		}
+		err = f.SetCellStyle(sheet, context.RateColumn+userRowIndex, context.RateColumn+userRowIndex, style)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+func (s *ExcelService) getProjectCosts(project *models.Project) ([]string, []float64) {
+	currencies := make([]string, 0, len(project.Users))
+	costs := make([]float64, 0, len(project.Users))
+	for _, user := range project.Users {
+		// hours are summed up by days like in user row
+		dateToTimeSpentSeconds := map[string]int{}
+		for _, issue := range user.Issues {
+			for _, effort := range issue.Efforts {
+				dateToTimeSpentSeconds[effort.Date] += effort.TimeSpentSeconds
+			}
+		}
+		hours := 0.0
+		for _, timeSpentSeconds := range dateToTimeSpentSeconds {
+			hours += s.convertSecondsToHours(timeSpentSeconds)
+		}
+		currencies = append(currencies, user.Currency)
+		costs = append(costs, user.Rate*hours)
+	}
+	return currencies, costs
+}
+
+// getCommonCurrency returns currency shared by all values, false is returned for mixed currencies.
+func (s *ExcelService) getCommonCurrency(currencies []string) (string, bool) {
+	if len(currencies) == 0 {
+		return "", true
+	}
+	for _, currency := range currencies {
+		if !strings.EqualFold(strings.TrimSpace(currency), strings.TrimSpace(currencies[0])) {
+			return "", false
+		}
+	}
+	return currencies[0], true
+}
+
+// setCostTotal sets formula of total cost if all costs are in the same currency. Costs in different currencies
+// are never summed up: subtotal per currency is written as text instead and a warning is logged.
+func (s *ExcelService) setCostTotal(f *excelize.File, sheet, cell, formula, subject string, currencies []string, costs []float64) error {
+	currencies, costs = s.getNonZeroCosts(currencies, costs)
+	if _, ok := s.getCommonCurrency(currencies); ok {
+		return f.SetCellFormula(sheet, cell, formula)
+	}
+
+	subtotals := s.formatSubtotals(currencies, costs)
+	log.Println("Warning: costs of", subject, "are in different currencies, total is split by currency:", subtotals)
+
+	return f.SetCellValue(sheet, cell, subtotals)
+}
+
+// formatTotal formats total the same way as it is written by setCostTotal: costs are summed up
+// if all of them are in the same currency, otherwise subtotals per currency are formatted.
+func (s *ExcelService) formatTotal(currencies []string, costs []float64) string {
+	currencies, costs = s.getNonZeroCosts(currencies, costs)
+	currency, ok := s.getCommonCurrency(currencies)
+	if !ok {
+		return s.formatSubtotals(currencies, costs)
+	}
+
+	total := 0.0
+	for _, cost := range costs {
+		total += cost
+	}
+	return utils.FormatCurrency(math.Round(total*100)/100, currency)
+}
+
+// getNonZeroCosts skips zero costs with their currencies, they do not matter for the sum.
+func (s *ExcelService) getNonZeroCosts(currencies []string, costs []float64) ([]string, []float64) {
+	var nonZeroCurrencies []string
+	var nonZeroCosts []float64
+	for i, cost := range costs {
+		if cost != 0 {
+			nonZeroCurrencies = append(nonZeroCurrencies, currencies[i])
+			nonZeroCosts = append(nonZeroCosts, cost)
+		}
+	}
+	return nonZeroCurrencies, nonZeroCosts
+}
+
+// formatSubtotals sums costs per currency in order of appearance, e.g. "€1200.00 + $300.00".
+func (s *ExcelService) formatSubtotals(currencies []string, costs []float64) string {
+	var order []string
+	currencyToCost := map[string]float64{}
+	for i, currency := range currencies {
+		currency = strings.ToUpper(strings.TrimSpace(currency))
+		if _, ok := currencyToCost[currency]; !ok {
+			order = append(order, currency)
+		}
+		currencyToCost[currency] += costs[i]
+	}
+
+	subtotals := make([]string, 0, len(order))
+	for _, currency := range order {
+		subtotals = append(subtotals, utils.FormatCurrency(math.Round(currencyToCost[currency]*100)/100, currency))
+	}
+	return strings.Join(subtotals, " + ")
+}
+
 func (s *ExcelService) shadeColor(color string, percent int64) string {
 	if len(color) == 0 {
 		return color
@@ -681,6 +891,5 @@ func (s *ExcelService) fillUserRow(f *excelize.File, sheet string, user *models.
 }
 
 func (s *ExcelService) convertSecondsToHours(seconds int) float64 {
-	value := float64(seconds) / 3600
-	return math.Round(value*100) / 100
+	return utils.ToHours(seconds)
 }
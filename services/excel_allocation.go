@@ -0,0 +1,109 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"pm-report/models"
+	"pm-report/utils"
+	"strconv"
+)
+
+func (s *ExcelService) fillAllocationSheet(f *excelize.File, report *models.Report) error {
+	sheet := s.getSheetName(report) + " Allocation"
+	allocation := report.Allocation
+
+	titles := []string{"User"}
+	widths := []float64{25}
+	for _, category := range allocation.Categories {
+		titles = append(titles, category)
+		widths = append(widths, 15)
+	}
+	titles = append(titles, "Total")
+	widths = append(widths, 15)
+
+	err := s.createTableSheet(f, sheet, titles, widths)
+	if err != nil {
+		return err
+	}
+
+	firstCostCol, err := excelize.ColumnNumberToName(2)
+	if err != nil {
+		return err
+	}
+	lastCostCol, err := excelize.ColumnNumberToName(len(allocation.Categories) + 1)
+	if err != nil {
+		return err
+	}
+	totalCol, err := excelize.ColumnNumberToName(len(allocation.Categories) + 2)
+	if err != nil {
+		return err
+	}
+
+	lastRowIndex := 1
+	var currencies []string
+
+	for _, user := range allocation.Users {
+		lastRowIndex++
+		rowIndex := strconv.Itoa(lastRowIndex)
+		currencies = append(currencies, user.Currency)
+
+		row := []interface{}{user.Name}
+		for _, cost := range user.Costs {
+			row = append(row, cost)
+		}
+		err = f.SetSheetRow(sheet, "A"+rowIndex, &row)
+		if err != nil {
+			return err
+		}
+		err = f.SetCellFormula(sheet, totalCol+rowIndex, "sum("+firstCostCol+rowIndex+":"+lastCostCol+rowIndex+")")
+		if err != nil {
+			return err
+		}
+		costStyle, err := f.NewStyle(&excelize.Style{NumFmt: 177, CustomNumFmt: utils.ToCurrencyNumFmt(user.Currency)})
+		if err != nil {
+			return err
+		}
+		err = f.SetCellStyle(sheet, firstCostCol+rowIndex, totalCol+rowIndex, costStyle)
+		if err != nil {
+			return err
+		}
+	}
+
+	// totals per category
+	lastUserRowIndex := strconv.Itoa(lastRowIndex)
+	lastRowIndex++
+	rowIndex := strconv.Itoa(lastRowIndex)
+
+	err = f.SetCellValue(sheet, "A"+rowIndex, "Total")
+	if err != nil {
+		return err
+	}
+	for i := 2; i <= len(allocation.Categories)+2; i++ {
+		col, err := excelize.ColumnNumberToName(i)
+		if err != nil {
+			return err
+		}
+
+		subject := "Total allocation"
+		costs := make([]float64, 0, len(allocation.Users))
+		for _, user := range allocation.Users {
+			if i <= len(allocation.Categories)+1 {
+				subject = allocation.Categories[i-2] + " allocation"
+				costs = append(costs, user.Costs[i-2])
+			} else {
+				costs = append(costs, user.GetTotalCost())
+			}
+		}
+
+		err = s.setCostTotal(f, sheet, col+rowIndex, "sum("+col+"2:"+col+lastUserRowIndex+")", subject, currencies, costs)
+		if err != nil {
+			return err
+		}
+	}
+
+	currency, _ := s.getCommonCurrency(currencies)
+	totalCostStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}, NumFmt: 177, CustomNumFmt: utils.ToCurrencyNumFmt(currency)})
+	if err != nil {
+		return err
+	}
+	return f.SetCellStyle(sheet, "A"+rowIndex, totalCol+rowIndex, totalCostStyle)
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"pm-report/models"
+	"pm-report/utils"
+	"strconv"
+)
+
+func (s *ExcelService) fillEpicsSheet(f *excelize.File, report *models.Report) error {
+	sheet := s.getSheetName(report) + " Epics"
+
+	err := s.createTableSheet(f, sheet, []string{"Project", "Epic", "Summary", "User", "Rate", "Hours", "Cost"}, []float64{12, 12, 40, 25, 9, 9, 12})
+	if err != nil {
+		return err
+	}
+
+	epicStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}, Fill: excelize.Fill{Color: []string{"#d9ead3"}, Type: "pattern", Pattern: 1}})
+	if err != nil {
+		return err
+	}
+
+	lastRowIndex := 1
+
+	for _, projectEpics := range report.Epics {
+		for _, epic := range projectEpics.Epics {
+			lastRowIndex++
+			epicRowIndex := strconv.Itoa(lastRowIndex)
+			firstUserRowIndex := strconv.Itoa(lastRowIndex + 1)
+			lastUserRowIndex := strconv.Itoa(lastRowIndex + len(epic.Users))
+
+			err = f.SetSheetRow(sheet, "A"+epicRowIndex, &[]interface{}{projectEpics.ProjectKey, epic.Key, epic.Summary})
+			if err != nil {
+				return err
+			}
+			err = f.SetCellFormula(sheet, "F"+epicRowIndex, "sum(F"+firstUserRowIndex+":F"+lastUserRowIndex+")")
+			if err != nil {
+				return err
+			}
+			err = f.SetCellStyle(sheet, "A"+epicRowIndex, "F"+epicRowIndex, epicStyle)
+			if err != nil {
+				return err
+			}
+			currencies := make([]string, 0, len(epic.Users))
+			costs := make([]float64, 0, len(epic.Users))
+			for _, user := range epic.Users {
+				currencies = append(currencies, user.Currency)
+				costs = append(costs, user.Rate*user.Hours)
+			}
+			err = s.setCostTotal(f, sheet, "G"+epicRowIndex, "sum(G"+firstUserRowIndex+":G"+lastUserRowIndex+")",
+				epic.Summary+" ("+projectEpics.ProjectKey+")", currencies, costs)
+			if err != nil {
+				return err
+			}
+			currency, _ := s.getCommonCurrency(currencies)
+			epicCostStyle, err := f.NewStyle(&excelize.Style{
+				Font:         &excelize.Font{Bold: true},
+				Fill:         excelize.Fill{Color: []string{"#d9ead3"}, Type: "pattern", Pattern: 1},
+				NumFmt:       177,
+				CustomNumFmt: utils.ToCurrencyNumFmt(currency),
+			})
+			if err != nil {
+				return err
+			}
+			err = f.SetCellStyle(sheet, "G"+epicRowIndex, "G"+epicRowIndex, epicCostStyle)
+			if err != nil {
+				return err
+			}
+
+			for _, user := range epic.Users {
+				lastRowIndex++
+				rowIndex := strconv.Itoa(lastRowIndex)
+
+				err = f.SetSheetRow(sheet, "A"+rowIndex, &[]interface{}{projectEpics.ProjectKey, epic.Key, epic.Summary, user.Name, user.Rate, user.Hours})
+				if err != nil {
+					return err
+				}
+				err = f.SetCellFormula(sheet, "G"+rowIndex, "E"+rowIndex+"*F"+rowIndex)
+				if err != nil {
+					return err
+				}
+				costStyle, err := f.NewStyle(&excelize.Style{NumFmt: 177, CustomNumFmt: utils.ToCurrencyNumFmt(user.Currency)})
+				if err != nil {
+					return err
+				}
+				err = f.SetCellStyle(sheet, "E"+rowIndex, "E"+rowIndex, costStyle)
+				if err != nil {
+					return err
+				}
+				err = f.SetCellStyle(sheet, "G"+rowIndex, "G"+rowIndex, costStyle)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
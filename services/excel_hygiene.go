@@ -0,0 +1,40 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"pm-report/models"
+	"strconv"
+)
+
+func (s *ExcelService) fillHygieneSheet(f *excelize.File, report *models.Report) error {
+	sheet := s.getSheetName(report) + " Hygiene"
+
+	err := s.createTableSheet(f, sheet,
+		[]string{"User", "Rule", "Project", "Issue", "Summary", "Date", "Hours"},
+		[]float64{25, 25, 12, 12, 40, 12, 9})
+	if err != nil {
+		return err
+	}
+
+	lastRowIndex := 1
+
+	for _, violation := range report.Violations {
+		lastRowIndex++
+		rowIndex := strconv.Itoa(lastRowIndex)
+
+		err = f.SetSheetRow(sheet, "A"+rowIndex, &[]interface{}{
+			violation.UserName,
+			violation.Rule,
+			violation.ProjectKey,
+			violation.IssueKey,
+			violation.Summary,
+			violation.Date,
+			violation.Hours,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
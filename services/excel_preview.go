@@ -0,0 +1,82 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"pm-report/models"
+	"strings"
+	"text/tabwriter"
+)
+
+// PrintPreview prints sheets and user rows of report which would be written to the report file.
+func (s *ExcelService) PrintPreview(w io.Writer, report *models.Report) error {
+	_, err := fmt.Fprintln(w, "Sheets of", s.filePath+":", strings.Join(s.getSheetNames(report), ", "))
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, err = fmt.Fprintln(tw, "PROJECT\tUSER\tPOSITION\tRATE\tHOURS\tCOST")
+	if err != nil {
+		return err
+	}
+
+	for _, project := range report.Projects {
+		projectSeconds := 0
+		var currencies []string
+		var costs []float64
+
+		for _, user := range project.Users {
+			var efforts []models.Effort
+			for _, issue := range user.Issues {
+				efforts = append(efforts, issue.Efforts...)
+			}
+
+			seconds := 0
+			for _, effort := range efforts {
+				seconds += effort.TimeSpentSeconds
+			}
+			cost := user.GetCost(efforts)
+
+			projectSeconds += seconds
+			currencies = append(currencies, user.Currency)
+			costs = append(costs, cost)
+
+			_, err = fmt.Fprintf(tw, "%s\t%s\t%s\t%.2f %s\t%.2f\t%.2f\n", project.Key, user.Name, user.Position, user.Rate, user.Currency, s.convertSecondsToHours(seconds), cost)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.2f\t%s\n", project.Key, "TOTAL", "", "", s.convertSecondsToHours(projectSeconds), s.formatTotal(currencies, costs))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (s *ExcelService) getSheetNames(report *models.Report) []string {
+	sheet := s.getSheetName(report)
+	sheets := []string{sheet}
+
+	if len(report.Epics) > 0 {
+		sheets = append(sheets, sheet+" Epics")
+	}
+	if len(report.Variances) > 0 {
+		sheets = append(sheets, sheet+" Variance")
+	}
+	if report.Allocation != nil {
+		sheets = append(sheets, sheet+" Allocation")
+	}
+	if len(report.Violations) > 0 {
+		sheets = append(sheets, sheet+" Hygiene")
+	}
+	if report.Sprint != nil {
+		sheets = append(sheets, sheet+" Summary")
+	}
+
+	return sheets
+}
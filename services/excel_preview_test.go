@@ -0,0 +1,31 @@
+package services
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExcelPrintPreview(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "report.xlsx")
+	report := newTestReport(newTestUser("a1", "Alice", "EUR", 50, 8), newTestUser("b2", "Bob", "", 25, 4))
+
+	var buffer bytes.Buffer
+	err := NewExcelService(filePath).PrintPreview(&buffer, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Sheets of " + filePath + ": September, September Epics, September Allocation\n" +
+		"PROJECT  USER   POSITION  RATE       HOURS  COST\n" +
+		"ABC      Alice            50.00 EUR  8.00   400.00\n" +
+		"ABC      Bob              25.00      4.00   100.00\n" +
+		"ABC      TOTAL                       12.00  €400.00 + $100.00\n"
+	if buffer.String() != want {
+		t.Fatalf("preview = %q, want %q", buffer.String(), want)
+	}
+	if _, err = os.Stat(filePath); !os.IsNotExist(err) {
+		t.Fatalf("%s is written by preview, err = %v", filePath, err)
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"pm-report/models"
+	"pm-report/utils"
+	"regexp"
+	"strconv"
+)
+
+var invalidSheetNameChars = regexp.MustCompile(`[\[\]:*?/\\]`)
+
+func (s *ExcelService) fillSprintSheet(f *excelize.File, report *models.Report) error {
+	sheet := s.getSheetName(report) + " Summary"
+
+	err := s.createTableSheet(f, sheet, []string{"Project", "User", "Rate", "Sprint hours", "Other hours", "Cost"}, []float64{12, 25, 9, 13, 13, 12})
+	if err != nil {
+		return err
+	}
+
+	lastRowIndex := 1
+	var currencies []string
+	var costs []float64
+
+	for _, user := range report.Sprint.Users {
+		lastRowIndex++
+		rowIndex := strconv.Itoa(lastRowIndex)
+		currencies = append(currencies, user.Currency)
+		costs = append(costs, user.Rate*user.SprintHours)
+
+		err = f.SetSheetRow(sheet, "A"+rowIndex, &[]interface{}{user.ProjectKey, user.Name, user.Rate, user.SprintHours, user.OtherHours})
+		if err != nil {
+			return err
+		}
+		err = f.SetCellFormula(sheet, "F"+rowIndex, "C"+rowIndex+"*D"+rowIndex)
+		if err != nil {
+			return err
+		}
+		costStyle, err := f.NewStyle(&excelize.Style{NumFmt: 177, CustomNumFmt: utils.ToCurrencyNumFmt(user.Currency)})
+		if err != nil {
+			return err
+		}
+		err = f.SetCellStyle(sheet, "C"+rowIndex, "C"+rowIndex, costStyle)
+		if err != nil {
+			return err
+		}
+		err = f.SetCellStyle(sheet, "F"+rowIndex, "F"+rowIndex, costStyle)
+		if err != nil {
+			return err
+		}
+	}
+
+	// totals
+	lastUserRowIndex := strconv.Itoa(lastRowIndex)
+	lastRowIndex++
+	rowIndex := strconv.Itoa(lastRowIndex)
+
+	err = f.SetCellValue(sheet, "A"+rowIndex, "Total")
+	if err != nil {
+		return err
+	}
+	for _, col := range []string{"D", "E"} {
+		err = f.SetCellFormula(sheet, col+rowIndex, "sum("+col+"2:"+col+lastUserRowIndex+")")
+		if err != nil {
+			return err
+		}
+	}
+	err = s.setCostTotal(f, sheet, "F"+rowIndex, "sum(F2:F"+lastUserRowIndex+")", report.Sprint.Name+" sprint", currencies, costs)
+	if err != nil {
+		return err
+	}
+
+	totalStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	err = f.SetCellStyle(sheet, "A"+rowIndex, "E"+rowIndex, totalStyle)
+	if err != nil {
+		return err
+	}
+	currency, _ := s.getCommonCurrency(currencies)
+	totalCostStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}, NumFmt: 177, CustomNumFmt: utils.ToCurrencyNumFmt(currency)})
+	if err != nil {
+		return err
+	}
+	return f.SetCellStyle(sheet, "F"+rowIndex, "F"+rowIndex, totalCostStyle)
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"path/filepath"
+	"pm-report/models"
+	"strconv"
+	"testing"
+)
+
+// findCell finds cell of the first row having the value in the first column.
+func findCell(t *testing.T, f *excelize.File, sheet, value, column string) string {
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		if len(row) > 0 && row[0] == value {
+			return column + strconv.Itoa(i+1)
+		}
+	}
+	t.Fatalf("%s row is not found in %s sheet", value, sheet)
+	return ""
+}
+
+func TestExcelSaveCostTotals(t *testing.T) {
+	tests := []struct {
+		name        string
+		report      *models.Report
+		wantFormula bool
+		wantValue   string
+	}{
+		{
+			name: "same currency",
+			report: newTestReport(
+				newTestUser("a1", "Alice", "EUR", 50, 2),
+				newTestUser("b2", "Bob", "EUR", 40, 1)),
+			wantFormula: true,
+		},
+		{
+			name: "mixed currencies",
+			report: newTestReport(
+				newTestUser("a1", "Alice", "EUR", 50, 2),
+				newTestUser("b2", "Bob", "USD", 40, 1),
+				newTestUser("c3", "Carol", "eur", 10, 3)),
+			wantValue: "€130.00 + $40.00",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "report.xlsx")
+			err := NewExcelService(filePath).Save(test.report)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := excelize.OpenFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			cells := map[string]string{
+				"September":            findCell(t, f, "September", "ABC", "F"),
+				"September Epics":      findCell(t, f, "September Epics", "ABC", "G"),
+				"September Allocation": findCell(t, f, "September Allocation", "Total", "D"),
+			}
+			for sheet, cell := range cells {
+				formula, err := f.GetCellFormula(sheet, cell)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if test.wantFormula {
+					if len(formula) == 0 {
+						t.Errorf("%s!%s has no total formula", sheet, cell)
+					}
+					continue
+				}
+
+				value, err := f.GetCellValue(sheet, cell)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(formula) > 0 || value != test.wantValue {
+					t.Errorf("%s!%s = %q (formula %q), want %q", sheet, cell, value, formula, test.wantValue)
+				}
+			}
+		})
+	}
+}
+
+func TestExcelFormatTotal(t *testing.T) {
+	tests := []struct {
+		name       string
+		currencies []string
+		costs      []float64
+		want       string
+	}{
+		{name: "no costs", want: "$0.00"},
+		{name: "default currency", currencies: []string{"", ""}, costs: []float64{10, 20.5}, want: "$30.50"},
+		{name: "same currency", currencies: []string{"EUR", "eur"}, costs: []float64{100, 30}, want: "€130.00"},
+		{name: "zero cost in another currency", currencies: []string{"EUR", "USD"}, costs: []float64{100, 0}, want: "€100.00"},
+		{name: "mixed currencies", currencies: []string{"EUR", "USD", "EUR"}, costs: []float64{100, 40, 30}, want: "€130.00 + $40.00"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := NewExcelService("").formatTotal(test.currencies, test.costs); got != test.want {
+				t.Fatalf("formatTotal() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"pm-report/models"
+	"strconv"
+)
+
+func (s *ExcelService) fillVarianceSheet(f *excelize.File, report *models.Report) error {
+	sheet := s.getSheetName(report) + " Variance"
+
+	err := s.createTableSheet(f, sheet,
+		[]string{"Project", "Issue", "Summary", "Estimate", "Remaining", "Logged this period", "Logged total", "Overrun"},
+		[]float64{12, 12, 40, 10, 11, 18, 13, 10})
+	if err != nil {
+		return err
+	}
+
+	percentStyle, err := f.NewStyle(&excelize.Style{NumFmt: 10})
+	if err != nil {
+		return err
+	}
+
+	lastRowIndex := 1
+
+	for _, variance := range report.Variances {
+		lastRowIndex++
+		rowIndex := strconv.Itoa(lastRowIndex)
+
+		err = f.SetSheetRow(sheet, "A"+rowIndex, &[]interface{}{
+			variance.ProjectKey,
+			variance.Key,
+			variance.Summary,
+			variance.EstimateHours,
+			variance.RemainingHours,
+			variance.PeriodHours,
+			variance.TotalHours,
+			variance.Overrun / 100,
+		})
+		if err != nil {
+			return err
+		}
+		err = f.SetCellStyle(sheet, "H"+rowIndex, "H"+rowIndex, percentStyle)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
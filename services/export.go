@@ -0,0 +1,32 @@
+package services
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"pm-report/models"
+)
+
+type ExportService struct {
+	filePath string
+}
+
+func NewExportService(filePath string) *ExportService {
+	return &ExportService{filePath: filePath}
+}
+
+func (s *ExportService) Save(report *models.Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(s.filePath, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Exported report to", s.filePath)
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"pm-report/models"
+	"testing"
+	"time"
+)
+
+// newTestUser creates report user with hours logged on ABC-1 issue on the first day of September 2026.
+func newTestUser(accountId, name, currency string, rate float64, hours int) models.User {
+	return models.User{
+		AccountId: accountId,
+		Name:      name,
+		Rate:      rate,
+		BaseRate:  rate,
+		Currency:  currency,
+		Issues: []models.Issue{
+			{Key: "ABC-1", Efforts: []models.Effort{{Date: "2026-09-01", TimeSpentSeconds: hours * 3600}}},
+		},
+	}
+}
+
+// testJiraHandler returns response to be encoded as json, non-zero status is written instead of the response.
+type testJiraHandler func(request *http.Request) (interface{}, int)
+
+// newTestJiraServer serves Jira API by request path, other paths respond with 404 Not Found.
+func newTestJiraServer(t *testing.T, pathToHandler map[string]testJiraHandler) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		handler, ok := pathToHandler[request.URL.Path]
+		if !ok {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		response, status := handler(request)
+		if status != 0 {
+			writer.WriteHeader(status)
+			return
+		}
+		if err := json.NewEncoder(writer).Encode(response); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// newTestReport creates report of September 2026 with the users in ABC project, epics and allocation are filled.
+func newTestReport(users ...models.User) *models.Report {
+	report := &models.Report{
+		DateFrom: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		DateTo:   time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC),
+		Projects: []models.Project{{Key: "ABC", Users: users}},
+	}
+	report.Epics = NewReportService(nil, nil, nil, models.TempoAppConfig{}).getEpics(report.Projects)
+	NewAllocationService(models.AllocationAppConfig{
+		Rules: []models.RuleAllocationAppConfig{{Category: "Frontend", Components: "Frontend"}},
+	}).Fill(report)
+	return report
+}
+
+// newTestProjectConfigWrapper creates project configs in the order they are written by every storage:
+// users are sorted by name with archived users last.
+func newTestProjectConfigWrapper() *models.ProjectConfigWrapper {
+	return &models.ProjectConfigWrapper{
+		ProjectConfigs: []models.ProjectConfig{
+			{
+				Key:         "ABC",
+				DisplayName: "Alpha Project",
+				Owner:       "ClientCo",
+				Manager:     "Max Lead",
+				Users: []models.UserConfig{
+					{AccountId: "a1", Email: "a1@example.com", Name: "Alice", Position: "Developer", Rate: 42.5, Currency: "EUR",
+						RateHistory: models.RateHistory{{EffectiveFrom: "2026-03-01", Rate: 50}, {EffectiveFrom: "2026-09-03", Rate: 60}},
+						Active:      true, LastSeen: "2026-09-15"},
+					{AccountId: "b2", Name: "Bob", Position: "QA", Active: true, LastSeen: "2026-09-16"},
+					{AccountId: "c3", Name: "Carol", Rate: 30, Currency: "USD", LastSeen: "2026-01-20", Archived: true},
+				},
+			},
+			{
+				Key:      "XYZ",
+				Users:    []models.UserConfig{{AccountId: "b2", Name: "Bob", Rate: 45, LastSeen: "2026-05-01"}},
+				Inactive: true,
+			},
+		},
+		People: []models.UserConfig{
+			{AccountId: "a1", Email: "a1@example.com", Name: "Alice", Position: "Developer", Rate: 40,
+				RateHistory: models.RateHistory{{EffectiveFrom: "2026-06-01", Rate: 44}}, LineManager: "Max Lead", Team: "Core"},
+			{AccountId: "b2", Name: "Bob", Position: "QA", Team: "QA"},
+		},
+		RateCards: []models.RateCardConfig{
+			{Position: "QA", Rate: 25},
+			{Position: "QA", Rate: 28, Currency: "EUR", Client: "ClientCo"},
+			{Position: "QA", Rate: 30, Project: "ABC"},
+		},
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"pm-report/models"
+	"pm-report/utils"
+	"sort"
+	"text/tabwriter"
+)
+
+const (
+	closedIssueHygieneRule       = "Logged on closed issue"
+	otherProjectIssueHygieneRule = "Issue in other project"
+	noEpicIssueHygieneRule       = "Issue without epic"
+)
+
+type HygieneService struct {
+}
+
+func NewHygieneService() *HygieneService {
+	return &HygieneService{}
+}
+
+// Check lists worklogs violating hygiene rules, issues must be enriched from Jira beforehand.
+func (s *HygieneService) Check(report *models.Report) {
+	var violations []models.HygieneViolation
+
+	for _, project := range report.Projects {
+		for _, user := range project.Users {
+			for _, issue := range user.Issues {
+				if len(issue.ProjectKey) == 0 {
+					continue // not resolved in jira
+				}
+
+				for _, effort := range issue.Efforts {
+					for _, rule := range s.getViolatedRules(project.Key, issue, effort) {
+						violations = append(violations, models.HygieneViolation{
+							ProjectKey: project.Key,
+							UserName:   user.Name,
+							Rule:       rule,
+							IssueKey:   issue.Key,
+							Summary:    issue.Summary,
+							Date:       effort.Date,
+							Hours:      utils.ToHours(effort.TimeSpentSeconds),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.UserName != b.UserName {
+			return a.UserName < b.UserName
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		if a.IssueKey != b.IssueKey {
+			return a.IssueKey < b.IssueKey
+		}
+		return a.Date < b.Date
+	})
+
+	report.Violations = violations
+}
+
+func (s *HygieneService) getViolatedRules(projectKey string, issue models.Issue, effort models.Effort) []string {
+	var rules []string
+
+	// time logged after the issue was resolved (or on closed issue without resolution date)
+	if issue.Closed && (len(issue.ResolutionDate) == 0 || effort.Date > issue.ResolutionDate) {
+		rules = append(rules, closedIssueHygieneRule)
+	}
+	if issue.ProjectKey != projectKey {
+		rules = append(rules, otherProjectIssueHygieneRule)
+	}
+	if len(issue.EpicKey) == 0 {
+		rules = append(rules, noEpicIssueHygieneRule)
+	}
+
+	return rules
+}
+
+// PrintSummary prints number of violations and hours per user and rule.
+func (s *HygieneService) PrintSummary(w io.Writer, report *models.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, err := fmt.Fprintln(tw, "USER\tRULE\tWORKLOGS\tHOURS")
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(report.Violations); {
+		user, rule := report.Violations[i].UserName, report.Violations[i].Rule
+
+		count := 0
+		hours := 0.0
+		for ; i < len(report.Violations) && report.Violations[i].UserName == user && report.Violations[i].Rule == rule; i++ {
+			count++
+			hours += report.Violations[i].Hours
+		}
+
+		_, err = fmt.Fprintf(tw, "%s\t%s\t%d\t%.2f\n", user, rule, count, hours)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
@@ -28,10 +28,61 @@ func NewInputArgsService() *InputArgsService {
 }
 
 func (s *InputArgsService) Parse(args []string) (*models.InputArgs, error) {
+	args, flags, err := s.parseFlags(args)
+	if err != nil {
+		return nil, err
+	}
+
+	inputArgs, err := s.parseCommand(args)
+	if err != nil {
+		return nil, err
+	}
+	inputArgs.DryRun = flags[models.DryRunFlag]
+	inputArgs.Strict = flags[models.StrictFlag]
+
+	log.Println("Parsed", utils.ToPrettyString("input args", inputArgs))
+
+	return inputArgs, nil
+}
+
+// parseFlags separates flags (they can be placed anywhere) from positional arguments.
+func (s *InputArgsService) parseFlags(args []string) ([]string, map[string]bool, error) {
+	var positionalArgs []string
+	flags := map[string]bool{}
+
+	for _, arg := range args {
+		arg = strings.Trim(arg, " ")
+		if !strings.HasPrefix(arg, "--") {
+			positionalArgs = append(positionalArgs, arg)
+			continue
+		}
+
+		switch arg {
+		case models.DryRunFlag, models.StrictFlag:
+			flags[arg] = true
+			log.Println("Flag input argument is accepted:", arg)
+		default:
+			return nil, nil, errors.New("error: flag as argument is not recognized: " + arg)
+		}
+	}
+
+	return positionalArgs, flags, nil
+}
+
+func (s *InputArgsService) parseCommand(args []string) (*models.InputArgs, error) {
 	if len(args) < 1 {
 		return nil, errors.New("error: not enough input arguments")
 	}
 
+	switch strings.Trim(args[0], " ") {
+	case models.CheckTokensCommand:
+		return s.parseCheckTokens(args[1:])
+	case models.SprintCommand:
+		return s.parseSprint(args[1:])
+	case models.ConvertCommand:
+		return s.parseConvert(args[1:])
+	}
+
 	// 1st (required)
 	monthArg := strings.Trim(args[0], " ")
 	monthTime, err := s.tryParseMonthAsNumber(monthArg)
@@ -58,16 +109,9 @@ func (s *InputArgsService) Parse(args []string) (*models.InputArgs, error) {
 	}
 
 	// 3rd (optional, default: file name)
-	appConfig := "AppConfig.yaml"
-	if len(args) >= 3 {
-		appConfig = args[2]
-		_, err := os.Stat(appConfig)
-		if err != nil {
-			return nil, err
-		}
-		log.Println("App config file is accepted:", appConfig)
-	} else {
-		log.Println("App config file is default:", appConfig)
+	appConfig, err := s.parseAppConfig(args, 2)
+	if err != nil {
+		return nil, err
 	}
 
 	dateFrom, dateTo, err := s.createDateRange(*monthTime, yearTime)
@@ -76,15 +120,101 @@ func (s *InputArgsService) Parse(args []string) (*models.InputArgs, error) {
 	}
 
 	inputArgs := &models.InputArgs{
+		Command:   models.ReportCommand,
 		DateFrom:  *dateFrom,
 		DateTo:    *dateTo,
-		AppConfig: appConfig,
+		AppConfig: *appConfig,
+	}
+	return inputArgs, nil
+}
+
+func (s *InputArgsService) parseCheckTokens(args []string) (*models.InputArgs, error) {
+	// 1st (optional, default: file name)
+	appConfig, err := s.parseAppConfig(args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	inputArgs := &models.InputArgs{
+		Command:   models.CheckTokensCommand,
+		AppConfig: *appConfig,
+	}
+	return inputArgs, nil
+}
+
+func (s *InputArgsService) parseSprint(args []string) (*models.InputArgs, error) {
+	if len(args) < 2 {
+		return nil, errors.New("error: board and sprint input arguments are required")
 	}
-	log.Println("Parsed", utils.ToPrettyString("input args", inputArgs))
 
+	// 1st (required)
+	boardArg := strings.Trim(args[0], " ")
+	boardId, err := strconv.Atoi(boardArg)
+	if err != nil {
+		return nil, errors.New("error: board as argument is not recognized: " + boardArg)
+	}
+	log.Println("Board input argument is accepted:", boardId)
+
+	// 2nd (required, sprint id or "last")
+	sprintArg := strings.ToLower(strings.Trim(args[1], " "))
+	if sprintArg != models.LastClosedSprint {
+		if _, err = strconv.Atoi(sprintArg); err != nil {
+			return nil, errors.New("error: sprint as argument is not recognized: " + sprintArg)
+		}
+	}
+	log.Println("Sprint input argument is accepted:", sprintArg)
+
+	// 3rd (optional, default: file name)
+	appConfig, err := s.parseAppConfig(args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	inputArgs := &models.InputArgs{
+		Command:   models.SprintCommand,
+		BoardId:   boardId,
+		Sprint:    sprintArg,
+		AppConfig: *appConfig,
+	}
 	return inputArgs, nil
 }
 
+func (s *InputArgsService) parseConvert(args []string) (*models.InputArgs, error) {
+	if len(args) < 2 {
+		return nil, errors.New("error: source and target project config files are required")
+	}
+
+	// 1st (required)
+	sourceFile := strings.Trim(args[0], " ")
+	log.Println("Source project config file is accepted:", sourceFile)
+
+	// 2nd (required)
+	targetFile := strings.Trim(args[1], " ")
+	log.Println("Target project config file is accepted:", targetFile)
+
+	inputArgs := &models.InputArgs{
+		Command:    models.ConvertCommand,
+		SourceFile: sourceFile,
+		TargetFile: targetFile,
+	}
+	return inputArgs, nil
+}
+
+func (s *InputArgsService) parseAppConfig(args []string, index int) (*string, error) {
+	appConfig := "AppConfig.yaml"
+	if len(args) > index {
+		appConfig = args[index]
+		_, err := os.Stat(appConfig)
+		if err != nil {
+			return nil, err
+		}
+		log.Println("App config file is accepted:", appConfig)
+	} else {
+		log.Println("App config file is default:", appConfig)
+	}
+	return &appConfig, nil
+}
+
 func (s *InputArgsService) tryParseMonthAsNumber(month string) (*time.Time, error) {
 	candidate := month
 	if _, err := strconv.ParseInt(month, 10, 64); err == nil {
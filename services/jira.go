@@ -0,0 +1,399 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"pm-report/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	jiraBatchSize          = 100
+	jiraWorklogBatchSize   = 1000
+	jiraEpicHierarchyLevel = 1
+	jiraDoneStatusCategory = "done"
+)
+
+var jiraIssueFields = []string{"summary", "issuetype", "status", "priority", "assignee", "parent",
+	"timeoriginalestimate", "timeestimate", "timespent", "components", "labels",
+	"project", "resolutiondate"}
+
+// JiraError is returned for unsuccessful Jira responses.
+type JiraError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *JiraError) Error() string {
+	return "Jira error: " + e.Status
+}
+
+type JiraService struct {
+	url                string
+	searchUrl          string
+	projectUrlTemplate string
+	userUrlTemplate    string
+	worklogUrlTemplate string
+	myselfUrl          string
+	sprintUrlTemplate  string
+	sprintsUrlTemplate string
+	email              string
+	token              string
+	resolveEmails      bool
+	projectKeyToJql    map[string]string
+
+	mutex         sync.Mutex
+	keyToIssue    map[string]models.JiraIssue // cache of resolved issues
+	missingIssues map[string]bool             // cache of keys which cannot be resolved
+	keyToProject  map[string]models.JiraProject
+	idToUser      map[string]models.JiraUser
+}
+
+func NewJiraService(jiraAppConfig models.JiraAppConfig) *JiraService {
+	baseUrl := strings.TrimRight(jiraAppConfig.Url, "/")
+
+	projectKeyToJql := map[string]string{}
+	for _, filter := range jiraAppConfig.Filters {
+		if len(strings.TrimSpace(filter.Jql)) > 0 {
+			projectKeyToJql[filter.Project] = filter.Jql
+		}
+	}
+
+	return &JiraService{
+		url:                baseUrl,
+		searchUrl:          baseUrl + "/rest/api/3/search/jql",
+		projectUrlTemplate: baseUrl + "/rest/api/3/project/%s",
+		userUrlTemplate:    baseUrl + "/rest/api/3/user?accountId=%s",
+		worklogUrlTemplate: baseUrl + "/rest/api/3/issue/%s/worklog?startedAfter=%d&startedBefore=%d&startAt=%d&maxResults=%d",
+		myselfUrl:          baseUrl + "/rest/api/3/myself",
+		sprintUrlTemplate:  baseUrl + "/rest/agile/1.0/sprint/%d",
+		sprintsUrlTemplate: baseUrl + "/rest/agile/1.0/board/%d/sprint?state=%s&startAt=%d&maxResults=%d",
+		email:              jiraAppConfig.Email,
+		token:              jiraAppConfig.Token,
+		resolveEmails:      jiraAppConfig.ResolveEmails,
+		projectKeyToJql:    projectKeyToJql,
+		keyToIssue:         map[string]models.JiraIssue{},
+		missingIssues:      map[string]bool{},
+		keyToProject:       map[string]models.JiraProject{},
+		idToUser:           map[string]models.JiraUser{},
+	}
+}
+
+func (s *JiraService) IsEnabled() bool {
+	return s != nil && len(s.url) > 0
+}
+
+// GetIssues resolves issues by keys in batches, already resolved issues are taken from cache.
+func (s *JiraService) GetIssues(issueKeys []string) (map[string]models.JiraIssue, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var unresolved []string
+	seen := map[string]bool{}
+	for _, issueKey := range issueKeys {
+		if _, ok := s.keyToIssue[issueKey]; ok || s.missingIssues[issueKey] || seen[issueKey] {
+			continue
+		}
+		seen[issueKey] = true
+		unresolved = append(unresolved, issueKey)
+	}
+
+	for start := 0; start < len(unresolved); start += jiraBatchSize {
+		end := start + jiraBatchSize
+		if end > len(unresolved) {
+			end = len(unresolved)
+		}
+		batch := unresolved[start:end]
+
+		issues, err := s.searchIssuesByKeys(batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			s.keyToIssue[issue.Key] = issue
+		}
+		for _, issueKey := range batch {
+			if _, ok := s.keyToIssue[issueKey]; !ok {
+				s.missingIssues[issueKey] = true
+			}
+		}
+
+		log.Println("Fetched jira issues:", len(issues), "of", len(batch))
+	}
+
+	result := map[string]models.JiraIssue{}
+	for _, issueKey := range issueKeys {
+		if issue, ok := s.keyToIssue[issueKey]; ok {
+			result[issueKey] = issue
+		}
+	}
+
+	return result, nil
+}
+
+func (s *JiraService) GetProject(projectKey string) (*models.JiraProject, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if project, ok := s.keyToProject[projectKey]; ok {
+		return &project, nil
+	}
+
+	project := models.JiraProject{}
+	err := s.fetchJiraResponse(http.MethodGet, fmt.Sprintf(s.projectUrlTemplate, url.PathEscape(projectKey)), nil, &project)
+	if err != nil {
+		return nil, err
+	}
+	s.keyToProject[projectKey] = project
+
+	log.Println("Fetched jira project:", projectKey)
+
+	return &project, nil
+}
+
+func (s *JiraService) IsEmailResolutionEnabled() bool {
+	return s.IsEnabled() && s.resolveEmails
+}
+
+// GetFilteredIssueKeys resolves keys of project issues matching configured JQL filter,
+// false is returned if there is no filter for the project.
+func (s *JiraService) GetFilteredIssueKeys(projectKey string) (map[string]bool, bool, error) {
+	jql, ok := s.projectKeyToJql[projectKey]
+	if !s.IsEnabled() || !ok {
+		return nil, false, nil
+	}
+
+	issues, err := s.searchIssues(fmt.Sprintf(`project = "%s" AND (%s)`, projectKey, jql), []string{"key"})
+	if err != nil {
+		return nil, false, err
+	}
+
+	issueKeys := map[string]bool{}
+	for _, issue := range issues {
+		issueKeys[issue.Key] = true
+	}
+
+	log.Println("Fetched jira issues matching filter for", projectKey, "project:", len(issueKeys))
+
+	return issueKeys, true, nil
+}
+
+// GetUser resolves user by account id, email is empty if it is hidden by user's privacy settings.
+func (s *JiraService) GetUser(accountId string) (*models.JiraUser, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if user, ok := s.idToUser[accountId]; ok {
+		return &user, nil
+	}
+
+	user := models.JiraUser{}
+	err := s.fetchJiraResponse(http.MethodGet, fmt.Sprintf(s.userUrlTemplate, url.QueryEscape(accountId)), nil, &user)
+	if err != nil {
+		return nil, err
+	}
+	s.idToUser[accountId] = user
+
+	log.Println("Fetched jira user:", user.DisplayName)
+
+	return &user, nil
+}
+
+// SearchIssueKeys resolves keys of issues matching JQL expression.
+func (s *JiraService) SearchIssueKeys(jql string) ([]string, error) {
+	issues, err := s.searchIssues(jql, []string{"key"})
+	if err != nil {
+		return nil, err
+	}
+
+	issueKeys := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		issueKeys = append(issueKeys, issue.Key)
+	}
+
+	return issueKeys, nil
+}
+
+// GetIssueWorklogs fetches worklogs of the issue started in the period (inclusive) widened by a day on each side:
+// the period is in UTC while worklogs are reported by local date of their start, so callers filter them by that date.
+func (s *JiraService) GetIssueWorklogs(issueKey string, dateFrom, dateTo time.Time) ([]models.JiraWorklog, error) {
+	startedAfter := dateFrom.AddDate(0, 0, -1).UnixMilli() - 1
+	startedBefore := dateTo.AddDate(0, 0, 2).UnixMilli()
+
+	var worklogs []models.JiraWorklog
+	startAt := 0
+
+	for {
+		worklogUrl := fmt.Sprintf(s.worklogUrlTemplate, url.PathEscape(issueKey), startedAfter, startedBefore, startAt, jiraWorklogBatchSize)
+
+		worklogResponse := &models.JiraWorklogResponse{}
+		err := s.fetchJiraResponse(http.MethodGet, worklogUrl, nil, worklogResponse)
+		if err != nil {
+			return nil, err
+		}
+		worklogs = append(worklogs, worklogResponse.Worklogs...)
+
+		startAt += len(worklogResponse.Worklogs)
+		if len(worklogResponse.Worklogs) == 0 || startAt >= worklogResponse.Total {
+			break
+		}
+	}
+
+	return worklogs, nil
+}
+
+func (s *JiraService) GetSprint(sprintId int) (*models.JiraSprint, error) {
+	sprint := &models.JiraSprint{}
+	err := s.fetchJiraResponse(http.MethodGet, fmt.Sprintf(s.sprintUrlTemplate, sprintId), nil, sprint)
+	if err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+func (s *JiraService) GetSprints(boardId int, state string) ([]models.JiraSprint, error) {
+	var sprints []models.JiraSprint
+	startAt := 0
+
+	for {
+		sprintsResponse := &models.JiraSprintsResponse{}
+		err := s.fetchJiraResponse(http.MethodGet, fmt.Sprintf(s.sprintsUrlTemplate, boardId, state, startAt, jiraBatchSize), nil, sprintsResponse)
+		if err != nil {
+			return nil, err
+		}
+		sprints = append(sprints, sprintsResponse.Values...)
+
+		startAt += len(sprintsResponse.Values)
+		if sprintsResponse.IsLast || len(sprintsResponse.Values) == 0 {
+			break
+		}
+	}
+
+	return sprints, nil
+}
+
+// CheckAccess makes sure the credentials are valid.
+func (s *JiraService) CheckAccess() error {
+	jiraUser := &models.JiraUser{}
+	return s.fetchJiraResponse(http.MethodGet, s.myselfUrl, nil, jiraUser)
+}
+
+// searchIssuesByKeys resolves a batch of issues, Jira rejects the whole batch (400 Bad Request)
+// if any key is deleted, moved or not visible, so rejected batch is retried key by key
+// and keys rejected on their own are skipped as unresolved.
+func (s *JiraService) searchIssuesByKeys(issueKeys []string) ([]models.JiraIssue, error) {
+	issues, err := s.searchIssues(s.getIssueKeysJql(issueKeys), jiraIssueFields)
+	if !s.isBadRequest(err) {
+		return issues, err
+	}
+	log.Println("Jira rejected batch of", len(issueKeys), "issues, resolving issues one by one:", err)
+
+	issues = nil
+	for _, issueKey := range issueKeys {
+		keyIssues, err := s.searchIssues(s.getIssueKeysJql([]string{issueKey}), jiraIssueFields)
+		if s.isBadRequest(err) {
+			log.Println("Cannot resolve jira issue", issueKey+":", err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, keyIssues...)
+	}
+
+	return issues, nil
+}
+
+func (s *JiraService) getIssueKeysJql(issueKeys []string) string {
+	return "issuekey in (" + strings.Join(issueKeys, ",") + ")"
+}
+
+func (s *JiraService) isBadRequest(err error) bool {
+	var jiraError *JiraError
+	return errors.As(err, &jiraError) && jiraError.StatusCode == http.StatusBadRequest
+}
+
+func (s *JiraService) searchIssues(jql string, fields []string) ([]models.JiraIssue, error) {
+	var issues []models.JiraIssue
+	nextPageToken := ""
+
+	for {
+		searchRequest := models.JiraSearchRequest{
+			Jql:           jql,
+			Fields:        fields,
+			MaxResults:    jiraBatchSize,
+			NextPageToken: nextPageToken,
+		}
+
+		searchResponse := &models.JiraSearchResponse{}
+		err := s.fetchJiraResponse(http.MethodPost, s.searchUrl, searchRequest, searchResponse)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, searchResponse.Issues...)
+
+		if searchResponse.IsLast || len(searchResponse.NextPageToken) == 0 {
+			break
+		}
+		nextPageToken = searchResponse.NextPageToken
+	}
+
+	return issues, nil
+}
+
+func (s *JiraService) fetchJiraResponse(method, requestUrl string, requestBody, responseBody interface{}) error {
+	client := http.Client{Timeout: time.Second * 60}
+
+	var body io.Reader
+	if requestBody != nil {
+		payload, err := json.Marshal(requestBody)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	request, err := http.NewRequest(method, requestUrl, body)
+	if err != nil {
+		return err
+	}
+
+	request.SetBasicAuth(s.email, s.token)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+
+	if response.Body != nil {
+		defer func() {
+			err := response.Body.Close()
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}()
+	}
+
+	if response.StatusCode != 200 {
+		return &JiraError{StatusCode: response.StatusCode, Status: response.Status}
+	}
+
+	payload, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payload, responseBody)
+}
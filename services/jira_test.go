@@ -0,0 +1,90 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"pm-report/models"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var issueKeysJqlPattern = regexp.MustCompile(`issuekey in \(([^)]*)\)`)
+
+// newJiraSearchServer serves issue search like Jira does: the whole search fails with 400 Bad Request
+// if any requested key does not exist, the status can be overridden for a key to simulate other failures.
+func newJiraSearchServer(t *testing.T, existingKeys []string, keyToStatus map[string]int) (*httptest.Server, *int) {
+	existing := map[string]bool{}
+	for _, key := range existingKeys {
+		existing[key] = true
+	}
+	requests := 0
+
+	server := newTestJiraServer(t, map[string]testJiraHandler{
+		"/rest/api/3/search/jql": func(request *http.Request) (interface{}, int) {
+			requests++
+
+			searchRequest := models.JiraSearchRequest{}
+			if err := json.NewDecoder(request.Body).Decode(&searchRequest); err != nil {
+				t.Error(err)
+				return nil, http.StatusInternalServerError
+			}
+			match := issueKeysJqlPattern.FindStringSubmatch(searchRequest.Jql)
+			if match == nil {
+				t.Errorf("unexpected jql: %s", searchRequest.Jql)
+				return nil, http.StatusInternalServerError
+			}
+
+			searchResponse := models.JiraSearchResponse{IsLast: true}
+			for _, key := range strings.Split(match[1], ",") {
+				if status, ok := keyToStatus[key]; ok {
+					return nil, status
+				}
+				if !existing[key] {
+					return nil, http.StatusBadRequest
+				}
+				searchResponse.Issues = append(searchResponse.Issues, models.JiraIssue{Key: key})
+			}
+			return searchResponse, 0
+		},
+	})
+
+	return server, &requests
+}
+
+func TestJiraGetIssuesFallsBackToSingleKeys(t *testing.T) {
+	server, requests := newJiraSearchServer(t, []string{"ABC-1", "ABC-3"}, nil)
+	jiraService := NewJiraService(models.JiraAppConfig{Url: server.URL})
+
+	issues, err := jiraService.GetIssues([]string{"ABC-1", "ABC-2", "ABC-3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(issues) != 2 || issues["ABC-1"].Key != "ABC-1" || issues["ABC-3"].Key != "ABC-3" {
+		t.Fatalf("issues = %v, want ABC-1 and ABC-3", issues)
+	}
+	if *requests != 4 {
+		t.Fatalf("requests = %d, want batch and 3 single key requests", *requests)
+	}
+
+	// missing key is cached as unresolved
+	_, err = jiraService.GetIssues([]string{"ABC-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *requests != 4 {
+		t.Fatalf("requests = %d, missing key should not be requested again", *requests)
+	}
+}
+
+func TestJiraGetIssuesFailsOnOtherErrors(t *testing.T) {
+	server, _ := newJiraSearchServer(t, []string{"ABC-1"}, map[string]int{"ABC-1": http.StatusUnauthorized})
+	jiraService := NewJiraService(models.JiraAppConfig{Url: server.URL})
+
+	_, err := jiraService.GetIssues([]string{"ABC-1"})
+	if err == nil || err.Error() != "Jira error: 401 Unauthorized" {
+		t.Fatalf("err = %v, want 401 Unauthorized", err)
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"pm-report/models"
+	"pm-report/utils"
+	"time"
+)
+
+// JiraWorklogService reads plain Jira worklogs for sites without Tempo,
+// credentials are taken from the underlying jira service, so token argument is ignored.
+type JiraWorklogService struct {
+	jiraService *JiraService
+}
+
+func NewJiraWorklogService(jiraService *JiraService) *JiraWorklogService {
+	return &JiraWorklogService{jiraService: jiraService}
+}
+
+func (s *JiraWorklogService) GetWorklogs(token, projectKey string, dateFrom, dateTo time.Time) ([]models.TempoResult, error) {
+	return s.fetchWorklogs(fmt.Sprintf(`project = "%s" AND %s`, projectKey, s.getUpdatedJql(dateFrom)), projectKey+" project", dateFrom, dateTo)
+}
+
+func (s *JiraWorklogService) GetAllWorklogs(token string, dateFrom, dateTo time.Time) (map[string][]models.TempoResult, error) {
+	results, err := s.fetchWorklogs(s.getUpdatedJql(dateFrom), "organization", dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	projectKeyToResults := map[string][]models.TempoResult{}
+	for _, result := range results {
+		projectKey := utils.ToProjectKey(result.Issue.Key)
+		projectKeyToResults[projectKey] = append(projectKeyToResults[projectKey], result)
+	}
+
+	return projectKeyToResults, nil
+}
+
+func (s *JiraWorklogService) CheckAccess(token, projectKey string, dateFrom, dateTo time.Time) error {
+	if len(projectKey) == 0 {
+		return s.jiraService.CheckAccess()
+	}
+
+	_, err := s.jiraService.GetProject(projectKey)
+	return err
+}
+
+// getUpdatedJql restricts issues to ones updated since the period start,
+// upper bound is not set since worklogs of the period can be logged later.
+func (s *JiraWorklogService) getUpdatedJql(dateFrom time.Time) string {
+	return fmt.Sprintf(`updated >= "%s"`, dateFrom.Format(dateFormat))
+}
+
+func (s *JiraWorklogService) fetchWorklogs(jql, subject string, dateFrom, dateTo time.Time) ([]models.TempoResult, error) {
+	issueKeys, err := s.jiraService.SearchIssueKeys(jql)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []models.TempoResult
+
+	for _, issueKey := range issueKeys {
+		worklogs, err := s.jiraService.GetIssueWorklogs(issueKey, dateFrom, dateTo)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, worklog := range worklogs {
+			if len(worklog.Started) < len(dateFormat) {
+				continue
+			}
+
+			startDate := worklog.Started[:len(dateFormat)] // local date of the worklog
+			date, err := time.Parse(dateFormat, startDate)
+			if err != nil {
+				return nil, err
+			}
+			if date.Before(dateFrom) || date.After(dateTo) {
+				continue
+			}
+
+			results = append(results, models.TempoResult{
+				Author: models.TempoAuthor{
+					AccountId:   worklog.Author.AccountId,
+					DisplayName: worklog.Author.DisplayName,
+				},
+				Issue:            models.TempoIssue{Key: issueKey},
+				StartDate:        startDate,
+				TimeSpentSeconds: worklog.TimeSpentSeconds,
+			})
+		}
+	}
+
+	log.Println("Fetched jira worklogs for", subject+":", len(results), "records from", len(issueKeys), "issues")
+
+	return results, nil
+}
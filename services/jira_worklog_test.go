@@ -0,0 +1,66 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"pm-report/models"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const jiraWorklogStartedFormat = "2006-01-02T15:04:05.000-0700"
+
+// newJiraWorklogServer serves a single issue with the worklogs, worklogs are filtered by started time like Jira does.
+func newJiraWorklogServer(t *testing.T, worklogs []models.JiraWorklog) *httptest.Server {
+	return newTestJiraServer(t, map[string]testJiraHandler{
+		"/rest/api/3/search/jql": func(request *http.Request) (interface{}, int) {
+			return models.JiraSearchResponse{Issues: []models.JiraIssue{{Key: "ABC-1"}}, IsLast: true}, 0
+		},
+		"/rest/api/3/issue/ABC-1/worklog": func(request *http.Request) (interface{}, int) {
+			startedAfter, _ := strconv.ParseInt(request.URL.Query().Get("startedAfter"), 10, 64)
+			startedBefore, _ := strconv.ParseInt(request.URL.Query().Get("startedBefore"), 10, 64)
+
+			worklogResponse := models.JiraWorklogResponse{}
+			for _, worklog := range worklogs {
+				started, err := time.Parse(jiraWorklogStartedFormat, worklog.Started)
+				if err != nil {
+					t.Error(err)
+					return nil, http.StatusInternalServerError
+				}
+				if started.UnixMilli() > startedAfter && started.UnixMilli() < startedBefore {
+					worklogResponse.Worklogs = append(worklogResponse.Worklogs, worklog)
+				}
+			}
+			worklogResponse.Total = len(worklogResponse.Worklogs)
+			return worklogResponse, 0
+		},
+	})
+}
+
+func TestJiraWorklogGetWorklogsByLocalDate(t *testing.T) {
+	server := newJiraWorklogServer(t, []models.JiraWorklog{
+		{Id: "1", Started: "2026-09-01T01:30:00.000+0300", TimeSpentSeconds: 3600}, // previous day in UTC
+		{Id: "2", Started: "2026-08-31T23:30:00.000+0000", TimeSpentSeconds: 7200},
+		{Id: "3", Started: "2026-09-30T23:30:00.000-0500", TimeSpentSeconds: 1800}, // next day in UTC
+		{Id: "4", Started: "2026-10-01T00:30:00.000-0500", TimeSpentSeconds: 900},
+	})
+	worklogService := NewJiraWorklogService(NewJiraService(models.JiraAppConfig{Url: server.URL}))
+
+	dateFrom := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	dateTo := time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)
+	results, err := worklogService.GetWorklogs("", "ABC", dateFrom, dateTo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, result := range results {
+		got = append(got, result.StartDate+" "+strconv.Itoa(result.TimeSpentSeconds))
+	}
+	want := []string{"2026-09-01 3600", "2026-09-30 1800"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("worklogs = %v, want %v", got, want)
+	}
+}
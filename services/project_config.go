@@ -1,154 +1,86 @@
 package services
 
 import (
-	"errors"
-	"fmt"
-	"github.com/xuri/excelize/v2"
 	"log"
 	"os"
 	"pm-report/models"
 	"pm-report/utils"
-	"sort"
-	"strconv"
+	"time"
 )
 
 type ProjectConfigService struct {
-	filePath string
+	filePath           string
+	markInactive       bool
+	archiveAfterMonths int  // 0 disables archiving of users
+	dryRun             bool // changes are printed instead of saving
+	jiraService        *JiraService
 }
 
-func NewProjectConfigService(filePath string) *ProjectConfigService {
+func NewProjectConfigService(filePath string, markInactive bool, archiveAfterMonths int, dryRun bool, jiraService *JiraService) *ProjectConfigService {
 	return &ProjectConfigService{
-		filePath: filePath,
+		filePath:           filePath,
+		markInactive:       markInactive,
+		archiveAfterMonths: archiveAfterMonths,
+		dryRun:             dryRun,
+		jiraService:        jiraService,
 	}
 }
 
 func (s *ProjectConfigService) Get() (*models.ProjectConfigWrapper, error) {
-	_, err := os.Stat(s.filePath)
-	if err != nil && errors.Is(err, os.ErrNotExist) {
-		return &models.ProjectConfigWrapper{}, nil
-	}
-
-	f, err := excelize.OpenFile(s.filePath)
+	storage, err := NewProjectConfigStorage(s.filePath)
 	if err != nil {
 		return nil, err
 	}
 
-	context := s.createContext()
-	var projectConfigs []models.ProjectConfig
-
-	for _, sheet := range f.GetSheetList() {
-		projectConfig, err := s.getProjectConfig(f, sheet, context)
-		if err != nil {
-			return nil, err
-		}
-		projectConfigs = append(projectConfigs, *projectConfig)
-	}
-
-	err = f.Close()
+	projectConfigWrapper, err := storage.Load()
 	if err != nil {
 		return nil, err
 	}
 
-	projectConfigWrapper := &models.ProjectConfigWrapper{ProjectConfigs: projectConfigs}
-
 	log.Println("Parsed", s.filePath, utils.ToPrettyString("config", projectConfigWrapper))
 
 	return projectConfigWrapper, nil
 }
 
-func (s *ProjectConfigService) getProjectConfig(f *excelize.File, sheet string, context *models.ProjectConfigContext) (*models.ProjectConfig, error) {
-	displayName, err := f.GetCellValue(sheet, context.Project.DisplayNameValueCell)
-	if err != nil {
-		return nil, err
+// Prefill fills empty project info from Jira project metadata: display name from project name
+// and manager from project lead. Values from the workbook keep precedence. Owner is not prefilled:
+// it is the client which rate cards are matched by, while Jira project category is not a client.
+func (s *ProjectConfigService) Prefill(projectConfig *models.ProjectConfig) {
+	if !s.jiraService.IsEnabled() {
+		return
 	}
-
-	owner, err := f.GetCellValue(sheet, context.Project.OwnerValueCell)
-	if err != nil {
-		return nil, err
+	if len(projectConfig.DisplayName) > 0 && len(projectConfig.Manager) > 0 {
+		return
 	}
 
-	manager, err := f.GetCellValue(sheet, context.Project.ManagerValueCell)
+	jiraProject, err := s.jiraService.GetProject(projectConfig.Key)
 	if err != nil {
-		return nil, err
+		log.Println("Cannot prefill project config for", projectConfig.Key, "project:", err)
+		return
 	}
 
-	userConfigs, err := s.getUserConfigs(f, sheet, context)
-	if err != nil {
-		return nil, err
+	if len(projectConfig.DisplayName) == 0 {
+		projectConfig.DisplayName = jiraProject.Name
 	}
-
-	projectConfig := models.ProjectConfig{
-		Key:              sheet,
-		DisplayName:      displayName,
-		Owner:            owner,
-		Manager:          manager,
-		UserNameToConfig: userConfigs,
+	if len(projectConfig.Manager) == 0 && jiraProject.Lead != nil {
+		projectConfig.Manager = jiraProject.Lead.DisplayName
 	}
-	return &projectConfig, nil
 }
 
-func (s *ProjectConfigService) getUserConfigs(f *excelize.File, sheet string, context *models.ProjectConfigContext) (map[string]models.UserConfig, error) {
-	userConfigs := map[string]models.UserConfig{}
-
-	rows, err := f.Rows(sheet)
-	if err != nil {
-		return nil, err
-	}
-
-	rowIndex := 0
-	for rows.Next() {
-		rowIndex++
-		if rowIndex <= context.User.HeaderRowIndex {
-			continue // skip
-		}
-
-		rowCols, err := rows.Columns()
-		if err != nil {
-			return nil, err
-		}
-
-		if rowCols == nil {
-			continue
-		}
-
-		userName := ""
-		if len(rowCols) > 0 {
-			userName = rowCols[0]
-		}
-		if len(userName) == 0 { // required
-			continue
-		}
-
-		position := ""
-		if len(rowCols) > 1 {
-			position = rowCols[1]
-		}
-
-		rate := 0
-		if len(rowCols) > 2 {
-			rate, err = strconv.Atoi(rowCols[2])
-			if err != nil {
-				return nil, err
-			}
-		}
+func (s *ProjectConfigService) Save(projectConfigWrapper *models.ProjectConfigWrapper, report *models.Report) error {
+	updatedProjectConfigWrapper := s.updateProjectConfigs(projectConfigWrapper, report)
 
-		userConfigs[userName] = models.UserConfig{
-			Position: position,
-			Rate:     rate,
-		}
+	if s.dryRun {
+		log.Println("Dry run, changes of", s.filePath, "are not saved:")
+		return s.printChanges(os.Stdout, s.getChanges(projectConfigWrapper, updatedProjectConfigWrapper, report))
 	}
 
-	if err = rows.Close(); err != nil {
-		return nil, err
+	storage, err := NewProjectConfigStorage(s.filePath)
+	if err != nil {
+		return err
 	}
 
-	return userConfigs, nil
-}
-
-func (s *ProjectConfigService) Save(projectConfigWrapper *models.ProjectConfigWrapper, report *models.Report) error {
-	updatedProjectConfigWrapper := s.updateProjectConfigs(projectConfigWrapper, report)
-
-	err := s.saveProjectConfigs(updatedProjectConfigWrapper)
+	err = storage.Save(updatedProjectConfigWrapper)
 	if err != nil {
 		return err
 	}
@@ -162,354 +94,148 @@ func (s *ProjectConfigService) updateProjectConfigs(projectConfigWrapper *models
 	var projectConfigs []models.ProjectConfig
 
 	for _, reportProject := range report.Projects {
-		updatedUserConfigs := map[string]models.UserConfig{}
+		var updatedUserConfigs []models.UserConfig
+		projectConfig := projectConfigWrapper.Get(reportProject.Key)
 
-		// actual users from report
+		// actual users from report, name is refreshed and account id is stored,
+		// project overrides are kept as is (defaults from people registry are not copied)
 		for _, reportUser := range reportProject.Users {
-			updatedUserConfigs[reportUser.Name] = models.UserConfig{
-				Position: reportUser.Position,
-				Rate:     reportUser.Rate,
+			userConfig := models.UserConfig{}
+			if projectConfig != nil {
+				if existingUserConfig := projectConfig.GetUser(reportUser.AccountId, reportUser.Name); existingUserConfig != nil {
+					userConfig = *existingUserConfig
+				}
+			}
+			userConfig.AccountId = reportUser.AccountId
+			userConfig.Name = reportUser.Name
+
+			// user with worklogs is active and returns from archive
+			userConfig.Active = true
+			userConfig.Archived = false
+			if lastEffortDate := reportUser.GetLastEffortDate(); lastEffortDate > userConfig.LastSeen {
+				userConfig.LastSeen = lastEffortDate
 			}
+
+			updatedUserConfigs = append(updatedUserConfigs, userConfig)
 		}
 
-		// missing users from config
-		projectConfig := projectConfigWrapper.Get(reportProject.Key)
+		// missing users from config, stale ones are archived
 		if projectConfig != nil {
-			for userName, userConfig := range projectConfig.UserNameToConfig {
-				if _, ok := updatedUserConfigs[userName]; !ok {
-					updatedUserConfigs[userName] = models.UserConfig{
-						Position: userConfig.Position,
-						Rate:     userConfig.Rate,
+			for _, userConfig := range projectConfig.Users {
+				if !s.containsUser(reportProject.Users, userConfig) {
+					userConfig.Active = false
+					if len(userConfig.LastSeen) == 0 {
+						// users which were never seen (e.g. added before the column existed) start archiving period now
+						userConfig.LastSeen = report.DateFrom.Format(effortDateFormat)
+					}
+					if !userConfig.Archived && s.isStale(userConfig, report.DateTo) {
+						userConfig.Archived = true
+						log.Println("User is archived in", reportProject.Key, "project config:", userConfig.Name)
 					}
+					updatedUserConfigs = append(updatedUserConfigs, userConfig)
 				}
 			}
 		}
 
 		updatedProjectConfig := models.ProjectConfig{
-			Key:              reportProject.Key,
-			DisplayName:      reportProject.DisplayName,
-			Owner:            reportProject.Owner,
-			Manager:          reportProject.Manager,
-			UserNameToConfig: updatedUserConfigs,
+			Key:         reportProject.Key,
+			DisplayName: reportProject.DisplayName,
+			Owner:       reportProject.Owner,
+			Manager:     reportProject.Manager,
+			Users:       updatedUserConfigs,
+		}
+		if projectConfig != nil {
+			// values prefilled from Jira are stored only for new configs,
+			// so that empty values of existing configs keep following Jira
+			updatedProjectConfig.DisplayName = projectConfig.DisplayName
+			updatedProjectConfig.Owner = projectConfig.Owner
+			updatedProjectConfig.Manager = projectConfig.Manager
 		}
 		projectConfigs = append(projectConfigs, updatedProjectConfig)
 	}
 
-	return &models.ProjectConfigWrapper{ProjectConfigs: projectConfigs}
-}
-
-func (s *ProjectConfigService) saveProjectConfigs(projectConfigWrapper *models.ProjectConfigWrapper) error {
-	f := excelize.NewFile()
-	context := s.createContext()
-
+	// projects which are not part of the run (e.g. reduced project list or removed token) are kept
 	for _, projectConfig := range projectConfigWrapper.ProjectConfigs {
-		s.createSheet(f, projectConfig.Key)
-
-		err := s.fillProjectInfo(f, projectConfig.Key, context, &projectConfig)
-		if err != nil {
-			return err
-		}
-
-		err = s.fillUsersHeader(f, projectConfig.Key, context, &projectConfig)
-		if err != nil {
-			return err
+		if s.containsProject(report.Projects, projectConfig.Key) {
+			continue
 		}
 
-		err = s.fillUsersBody(f, projectConfig.Key, context, &projectConfig)
-		if err != nil {
-			return err
+		if s.markInactive && !projectConfig.Inactive {
+			projectConfig.Inactive = true
+			log.Println("Project config is marked as inactive:", projectConfig.Key)
 		}
+		projectConfigs = append(projectConfigs, projectConfig)
 	}
 
-	err := f.SaveAs(s.filePath)
-	if err != nil {
-		return err
-	}
+	people := s.updatePeople(projectConfigWrapper.People, report)
 
-	err = f.Close()
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return &models.ProjectConfigWrapper{ProjectConfigs: projectConfigs, People: people, RateCards: projectConfigWrapper.RateCards}
 }
 
-func (s *ProjectConfigService) createSheet(f *excelize.File, sheet string) {
-	f.NewSheet(sheet)
-
-	if f.GetSheetIndex("Sheet1") != -1 {
-		f.DeleteSheet("Sheet1")
-	}
-}
-
-func (s *ProjectConfigService) fillProjectInfo(f *excelize.File, sheet string, context *models.ProjectConfigContext, projectConfig *models.ProjectConfig) error {
-	err := f.MergeCell(sheet, "A1", "C1")
-	if err != nil {
-		return err
-	}
-	alignment := excelize.Alignment{Horizontal: "center"}
-	borders := []excelize.Border{
-		{Type: "top", Color: "#000000", Style: 1},
-		{Type: "left", Color: "#000000", Style: 1},
-		{Type: "bottom", Color: "#000000", Style: 1},
-		{Type: "right", Color: "#000000", Style: 1},
-	}
-	headerFont := excelize.Font{Color: "#ffffff", Bold: true}
-	fill := excelize.Fill{Color: []string{"#009a00"}, Type: "pattern", Pattern: 1}
-	style, err := f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &headerFont, Border: borders, Fill: fill})
-	if err != nil {
-		return err
-	}
-	err = f.SetCellStyle(sheet, context.Project.HeaderCell, context.Project.HeaderCell, style)
-	if err != nil {
-		return err
-	}
-	err = f.SetCellValue(sheet, context.Project.HeaderCell, "Project Info")
-	if err != nil {
-		return err
-	}
-
-	col, row, err := excelize.CellNameToCoordinates(context.Project.KeyValueCell)
-	if err != nil {
-		return err
-	}
-	cell, err := excelize.CoordinatesToCellName(col+1, row)
-	if err != nil {
-		return err
-	}
-	err = f.MergeCell(sheet, context.Project.KeyValueCell, cell)
-	if err != nil {
-		return err
-	}
-	err = f.SetCellValue(sheet, context.Project.KeyTitleCell, "Key")
-	if err != nil {
-		return err
-	}
-	err = f.SetCellValue(sheet, context.Project.KeyValueCell, sheet)
-	if err != nil {
-		return err
-	}
+// updatePeople adds new people from report to registry, names are refreshed, account ids and emails are stored.
+func (s *ProjectConfigService) updatePeople(people []models.UserConfig, report *models.Report) []models.UserConfig {
+	registry := &models.ProjectConfigWrapper{People: make([]models.UserConfig, len(people))}
+	copy(registry.People, people)
 
-	col, row, err = excelize.CellNameToCoordinates(context.Project.DisplayNameValueCell)
-	if err != nil {
-		return err
-	}
-	cell, err = excelize.CoordinatesToCellName(col+1, row)
-	if err != nil {
-		return err
-	}
-	err = f.MergeCell(sheet, context.Project.DisplayNameValueCell, cell)
-	if err != nil {
-		return err
-	}
-	err = f.SetCellValue(sheet, context.Project.DisplayNameTitleCell, "Display Name")
-	if err != nil {
-		return err
-	}
-	err = f.SetCellValue(sheet, context.Project.DisplayNameValueCell, projectConfig.DisplayName)
-	if err != nil {
-		return err
-	}
-
-	col, row, err = excelize.CellNameToCoordinates(context.Project.OwnerValueCell)
-	if err != nil {
-		return err
-	}
-	cell, err = excelize.CoordinatesToCellName(col+1, row)
-	if err != nil {
-		return err
-	}
-	err = f.MergeCell(sheet, context.Project.OwnerValueCell, cell)
-	if err != nil {
-		return err
-	}
-	err = f.SetCellValue(sheet, context.Project.OwnerTitleCell, "Owner")
-	if err != nil {
-		return err
-	}
-	err = f.SetCellValue(sheet, context.Project.OwnerValueCell, projectConfig.Owner)
-	if err != nil {
-		return err
-	}
+	for _, reportProject := range report.Projects {
+		for _, reportUser := range reportProject.Users {
+			person := registry.GetPerson(reportUser.AccountId, reportUser.Name)
+			if person == nil {
+				registry.People = append(registry.People, models.UserConfig{})
+				person = &registry.People[len(registry.People)-1]
+			}
+			person.AccountId = reportUser.AccountId
+			person.Name = reportUser.Name
 
-	col, row, err = excelize.CellNameToCoordinates(context.Project.ManagerValueCell)
-	if err != nil {
-		return err
-	}
-	cell, err = excelize.CoordinatesToCellName(col+1, row)
-	if err != nil {
-		return err
-	}
-	err = f.MergeCell(sheet, context.Project.ManagerValueCell, cell)
-	if err != nil {
-		return err
-	}
-	err = f.SetCellValue(sheet, context.Project.ManagerTitleCell, "Manager")
-	if err != nil {
-		return err
-	}
-	err = f.SetCellValue(sheet, context.Project.ManagerValueCell, projectConfig.Manager)
-	if err != nil {
-		return err
+			person.Email = s.resolveEmail(person.AccountId, person.Name, person.Email)
+		}
 	}
 
-	return nil
+	return registry.People
 }
 
-func (s *ProjectConfigService) fillUsersHeader(f *excelize.File, sheet string, context *models.ProjectConfigContext, projectConfig *models.ProjectConfig) error {
-	err := f.SetColWidth(sheet, context.User.NameColumn, context.User.NameColumn, 30)
-	if err != nil {
-		return err
-	}
-
-	err = f.SetColWidth(sheet, context.User.PositionColumn, context.User.PositionColumn, 30)
-	if err != nil {
-		return err
-	}
-
-	err = f.SetColWidth(sheet, context.User.RateColumn, context.User.RateColumn, 10)
-	if err != nil {
-		return err
-	}
-
-	style, err := f.NewStyle(&excelize.Style{NumFmt: 177})
-	if err != nil {
-		return err
-	}
-	err = f.SetColStyle(sheet, context.User.RateColumn, style)
-	if err != nil {
-		return err
-	}
-
-	rowIndex := strconv.Itoa(context.User.HeaderRowIndex - 1)
-
-	err = f.MergeCell(sheet, context.User.NameColumn+rowIndex, context.User.RateColumn+rowIndex)
-	if err != nil {
-		return err
-	}
-
-	rowIndex = strconv.Itoa(context.User.HeaderRowIndex)
-
-	alignment := excelize.Alignment{Horizontal: "center"}
-	borders := []excelize.Border{
-		{Type: "top", Color: "#000000", Style: 1},
-		{Type: "left", Color: "#000000", Style: 1},
-		{Type: "bottom", Color: "#000000", Style: 1},
-		{Type: "right", Color: "#000000", Style: 1},
-	}
-	headerFont := excelize.Font{Color: "#ffffff", Bold: true}
-	fill := excelize.Fill{Color: []string{"#009a00"}, Type: "pattern", Pattern: 1}
-	style, err = f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &headerFont, Border: borders, Fill: fill})
-	if err != nil {
-		return err
-	}
-	err = f.SetCellStyle(sheet, context.User.NameColumn+rowIndex, context.User.RateColumn+rowIndex, style)
-	if err != nil {
-		return err
-	}
-
-	err = f.SetCellValue(sheet, context.User.NameColumn+rowIndex, "Name")
-	if err != nil {
-		return err
-	}
-
-	err = f.SetCellValue(sheet, context.User.PositionColumn+rowIndex, "Position")
-	if err != nil {
-		return err
-	}
-
-	err = f.SetCellValue(sheet, context.User.RateColumn+rowIndex, "Rate")
-	if err != nil {
-		return err
+// isStale checks whether user has no worklogs for configured number of months before the date,
+// users without last seen date are not considered stale.
+func (s *ProjectConfigService) isStale(userConfig models.UserConfig, date time.Time) bool {
+	if s.archiveAfterMonths <= 0 || len(userConfig.LastSeen) == 0 {
+		return false
 	}
-
-	return nil
+	return userConfig.LastSeen < date.AddDate(0, -s.archiveAfterMonths, 0).Format(effortDateFormat)
 }
 
-func (s *ProjectConfigService) fillUsersBody(f *excelize.File, sheet string, context *models.ProjectConfigContext, projectConfig *models.ProjectConfig) error {
-	lastRowIndex := context.User.HeaderRowIndex
-	users := projectConfig.UserNameToConfig
-
-	userNames := make([]string, 0, len(users))
-	for userName := range users {
-		userNames = append(userNames, userName)
-	}
-	sort.Strings(userNames)
-
-	conditionalFormat, err := s.getConditionalFormat(f)
-	if err != nil {
-		return err
-	}
-
-	for _, userName := range userNames {
-		lastRowIndex++
-		rowIndex := strconv.Itoa(lastRowIndex)
-
-		err = f.SetConditionalFormat(sheet, context.User.RateColumn+rowIndex, *conditionalFormat)
-		if err != nil {
-			return err
+func (s *ProjectConfigService) containsProject(projects []models.Project, projectKey string) bool {
+	for _, project := range projects {
+		if project.Key == projectKey {
+			return true
 		}
+	}
+	return false
+}
 
-		err = f.SetCellValue(sheet, context.User.NameColumn+rowIndex, userName)
-		if err != nil {
-			return err
+func (s *ProjectConfigService) containsUser(users []models.User, userConfig models.UserConfig) bool {
+	for _, user := range users {
+		if len(userConfig.AccountId) > 0 && userConfig.AccountId == user.AccountId {
+			return true
 		}
-
-		err = f.SetCellValue(sheet, context.User.PositionColumn+rowIndex, users[userName].Position)
-		if err != nil {
-			return err
-		}
-
-		err = f.SetCellValue(sheet, context.User.RateColumn+rowIndex, users[userName].Rate)
-		if err != nil {
-			return err
+		if len(userConfig.AccountId) == 0 && userConfig.Name == user.Name {
+			return true
 		}
 	}
-
-	return nil
+	return false
 }
 
-func (s *ProjectConfigService) createContext() *models.ProjectConfigContext {
-	return &models.ProjectConfigContext{
-		Project: models.InfoProjectConfigContext{
-			HeaderCell: "A1",
-
-			KeyTitleCell: "A2",
-			KeyValueCell: "B2",
-
-			DisplayNameTitleCell: "A3",
-			DisplayNameValueCell: "B3",
-
-			OwnerTitleCell: "A4",
-			OwnerValueCell: "B4",
-
-			ManagerTitleCell: "A5",
-			ManagerValueCell: "B5",
-		},
-		User: models.UserProjectConfigContext{
-			HeaderRowIndex: 7,
-			NameColumn:     "A",
-			PositionColumn: "B",
-			RateColumn:     "C",
-		},
+// resolveEmail resolves missing email from Jira user profile, the email stays empty if the user cannot be fetched
+// (e.g. deactivated or deleted account), so that the report is not lost.
+func (s *ProjectConfigService) resolveEmail(accountId, name, email string) string {
+	if len(email) > 0 || len(accountId) == 0 || !s.jiraService.IsEmailResolutionEnabled() {
+		return email
 	}
-}
 
-func (s *ProjectConfigService) getConditionalFormat(f *excelize.File) (*string, error) {
-	format, err := f.NewConditionalStyle(`{
-		"font": {
-			"color": "#9A0511"
-		},
-		"fill": {
-			"type": "pattern",
-			"color": ["#FEC7CE"],
-			"pattern": 1
-		}
-	}`)
+	jiraUser, err := s.jiraService.GetUser(accountId)
 	if err != nil {
-		return nil, err
+		log.Println("Warning: cannot resolve email of", name+":", err)
+		return email
 	}
 
-	formatSet := fmt.Sprintf(`[{ "type": "cell", "criteria": "=", "format": %d, "value": "0" }]`, format)
-	return &formatSet, nil
+	return jiraUser.EmailAddress
 }
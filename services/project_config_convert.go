@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"os"
+)
+
+type ProjectConfigConvertService struct {
+}
+
+func NewProjectConfigConvertService() *ProjectConfigConvertService {
+	return &ProjectConfigConvertService{}
+}
+
+// Convert copies project configs to a file of another format (chosen by file extension),
+// target file must not exist, so that no configs are overwritten by mistake.
+func (s *ProjectConfigConvertService) Convert(sourceFilePath, targetFilePath string) error {
+	_, err := os.Stat(sourceFilePath)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(targetFilePath)
+	if err == nil {
+		return errors.New("error: target project config file already exists: " + targetFilePath)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	sourceStorage, err := NewProjectConfigStorage(sourceFilePath)
+	if err != nil {
+		return err
+	}
+
+	targetStorage, err := NewProjectConfigStorage(targetFilePath)
+	if err != nil {
+		return err
+	}
+
+	projectConfigWrapper, err := sourceStorage.Load()
+	if err != nil {
+		return err
+	}
+
+	err = targetStorage.Save(projectConfigWrapper)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Converted", sourceFilePath, "to", targetFilePath, "projects:", len(projectConfigWrapper.ProjectConfigs),
+		"people:", len(projectConfigWrapper.People), "rate cards:", len(projectConfigWrapper.RateCards))
+
+	return nil
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"pm-report/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	projectCsvRecord  = "project"
+	userCsvRecord     = "user"
+	personCsvRecord   = "person"
+	rateCardCsvRecord = "rate card"
+)
+
+var projectConfigCsvTitles = []string{"Record", "Project", "Name", "Position", "Rate", "Currency", "Effective From",
+	"Account Id", "Email", "Line Manager", "Team", "Active", "Last Seen", "Archived",
+	"Display Name", "Owner", "Manager", "Inactive", "Client"}
+
+// CsvProjectConfigStorage keeps project configs in a single csv file, kind of each row is defined by `Record` column:
+// project (project info), user (project user), person (people registry) or rate card. Like in workbook,
+// additional rows of the same user or person with `Effective From` date make rate history.
+type CsvProjectConfigStorage struct {
+	filePath string
+}
+
+func NewCsvProjectConfigStorage(filePath string) *CsvProjectConfigStorage {
+	return &CsvProjectConfigStorage{filePath: filePath}
+}
+
+func (s *CsvProjectConfigStorage) Load() (*models.ProjectConfigWrapper, error) {
+	file, err := os.Open(s.filePath)
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return &models.ProjectConfigWrapper{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return &models.ProjectConfigWrapper{}, nil
+	}
+
+	// columns are found by titles, so their order does not matter
+	titleToIndex := map[string]int{}
+	for i, title := range records[0] {
+		titleToIndex[strings.TrimSpace(title)] = i
+	}
+
+	projectConfigWrapper := &models.ProjectConfigWrapper{}
+	people := &models.ProjectConfig{Key: peopleSheet}
+
+	for i, record := range records[1:] {
+		line := i + 2
+		project := s.getValue(record, titleToIndex, "Project")
+
+		rate := 0.0
+		if rateValue := s.getValue(record, titleToIndex, "Rate"); len(rateValue) > 0 {
+			rate, err = strconv.ParseFloat(rateValue, 64)
+			if err != nil {
+				return nil, fmt.Errorf("error: rate in line %d of %s is not a number: %s", line, s.filePath, rateValue)
+			}
+		}
+
+		switch strings.ToLower(s.getValue(record, titleToIndex, "Record")) {
+		case projectCsvRecord:
+			if len(project) == 0 {
+				return nil, fmt.Errorf("error: project in line %d of %s is required", line, s.filePath)
+			}
+			projectConfig := s.getProjectConfig(projectConfigWrapper, project)
+			projectConfig.DisplayName = s.getValue(record, titleToIndex, "Display Name")
+			projectConfig.Owner = s.getValue(record, titleToIndex, "Owner")
+			projectConfig.Manager = s.getValue(record, titleToIndex, "Manager")
+			projectConfig.Inactive = s.getValue(record, titleToIndex, "Inactive") == yesValue
+
+		case userCsvRecord, personCsvRecord:
+			userConfig, rateConfig, err := s.getUserRow(record, titleToIndex, line, rate)
+			if err != nil {
+				return nil, err
+			}
+			if len(userConfig.Name) == 0 { // required
+				continue
+			}
+
+			if strings.EqualFold(s.getValue(record, titleToIndex, "Record"), personCsvRecord) {
+				people.AddUserRow(*userConfig, *rateConfig)
+				continue
+			}
+			if len(project) == 0 {
+				return nil, fmt.Errorf("error: project in line %d of %s is required", line, s.filePath)
+			}
+			s.getProjectConfig(projectConfigWrapper, project).AddUserRow(*userConfig, *rateConfig)
+
+		case rateCardCsvRecord:
+			position := s.getValue(record, titleToIndex, "Position")
+			if len(position) == 0 { // required
+				continue
+			}
+			projectConfigWrapper.RateCards = append(projectConfigWrapper.RateCards, models.RateCardConfig{
+				Position: position,
+				Rate:     rate,
+				Currency: strings.ToUpper(s.getValue(record, titleToIndex, "Currency")),
+				Project:  project,
+				Client:   s.getValue(record, titleToIndex, "Client"),
+			})
+
+		case "":
+			continue
+
+		default:
+			return nil, fmt.Errorf("error: record in line %d of %s is not recognized: %s", line, s.filePath, s.getValue(record, titleToIndex, "Record"))
+		}
+	}
+
+	projectConfigWrapper.People = people.Users
+
+	return projectConfigWrapper, nil
+}
+
+// getValue finds value of the column by its title, empty if there is no such column.
+func (s *CsvProjectConfigStorage) getValue(record []string, titleToIndex map[string]int, title string) string {
+	index, ok := titleToIndex[title]
+	if !ok || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+// getProjectConfig finds project config by key, it is added if it is not listed yet.
+func (s *CsvProjectConfigStorage) getProjectConfig(projectConfigWrapper *models.ProjectConfigWrapper, key string) *models.ProjectConfig {
+	for i := range projectConfigWrapper.ProjectConfigs {
+		if projectConfigWrapper.ProjectConfigs[i].Key == key {
+			return &projectConfigWrapper.ProjectConfigs[i]
+		}
+	}
+	projectConfigWrapper.ProjectConfigs = append(projectConfigWrapper.ProjectConfigs, models.ProjectConfig{Key: key})
+	return &projectConfigWrapper.ProjectConfigs[len(projectConfigWrapper.ProjectConfigs)-1]
+}
+
+func (s *CsvProjectConfigStorage) getUserRow(record []string, titleToIndex map[string]int, line int, rate float64) (*models.UserConfig, *models.RateConfig, error) {
+	effectiveFrom := s.getValue(record, titleToIndex, "Effective From")
+	if len(effectiveFrom) > 0 {
+		if _, err := time.Parse(effortDateFormat, effectiveFrom); err != nil {
+			return nil, nil, fmt.Errorf("error: effective date in line %d of %s is not recognized: %s", line, s.filePath, effectiveFrom)
+		}
+	}
+
+	lastSeen := s.getValue(record, titleToIndex, "Last Seen")
+	if len(lastSeen) > 0 {
+		if _, err := time.Parse(effortDateFormat, lastSeen); err != nil {
+			return nil, nil, fmt.Errorf("error: last seen date in line %d of %s is not recognized: %s", line, s.filePath, lastSeen)
+		}
+	}
+
+	userConfig := &models.UserConfig{
+		AccountId: s.getValue(record, titleToIndex, "Account Id"),
+		Email:     s.getValue(record, titleToIndex, "Email"),
+		Name:      s.getValue(record, titleToIndex, "Name"),
+		Position:  s.getValue(record, titleToIndex, "Position"),
+		Currency:  strings.ToUpper(s.getValue(record, titleToIndex, "Currency")),
+
+		LineManager: s.getValue(record, titleToIndex, "Line Manager"),
+		Team:        s.getValue(record, titleToIndex, "Team"),
+
+		Active:   s.getValue(record, titleToIndex, "Active") == yesValue,
+		LastSeen: lastSeen,
+		Archived: s.getValue(record, titleToIndex, "Archived") == yesValue,
+	}
+	return userConfig, &models.RateConfig{EffectiveFrom: effectiveFrom, Rate: rate}, nil
+}
+
+func (s *CsvProjectConfigStorage) Save(projectConfigWrapper *models.ProjectConfigWrapper) error {
+	records := [][]string{projectConfigCsvTitles}
+
+	for _, person := range projectConfigWrapper.People {
+		records = append(records, s.getUserRecords(personCsvRecord, "", person)...)
+	}
+
+	for _, rateCard := range projectConfigWrapper.RateCards {
+		records = append(records, s.toRecord(map[string]string{
+			"Record":   rateCardCsvRecord,
+			"Project":  rateCard.Project,
+			"Position": rateCard.Position,
+			"Rate":     s.formatRate(rateCard.Rate),
+			"Currency": rateCard.Currency,
+			"Client":   rateCard.Client,
+		}))
+	}
+
+	for _, projectConfig := range projectConfigWrapper.ProjectConfigs {
+		records = append(records, s.toRecord(map[string]string{
+			"Record":       projectCsvRecord,
+			"Project":      projectConfig.Key,
+			"Display Name": projectConfig.DisplayName,
+			"Owner":        projectConfig.Owner,
+			"Manager":      projectConfig.Manager,
+			"Inactive":     s.formatBool(projectConfig.Inactive),
+		}))
+
+		for _, user := range projectConfig.Users {
+			records = append(records, s.getUserRecords(userCsvRecord, projectConfig.Key, user)...)
+		}
+	}
+
+	file, err := os.Create(s.filePath)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(file)
+	err = writer.WriteAll(records)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// getUserRecords makes row per rate (base rate and rate history) like in workbook.
+func (s *CsvProjectConfigStorage) getUserRecords(recordType, project string, user models.UserConfig) [][]string {
+	var records [][]string
+
+	for _, rateConfig := range user.GetRateConfigs() {
+		titleToValue := map[string]string{
+			"Record":         recordType,
+			"Project":        project,
+			"Name":           user.Name,
+			"Position":       user.Position,
+			"Rate":           s.formatRate(rateConfig.Rate),
+			"Currency":       user.Currency,
+			"Effective From": rateConfig.EffectiveFrom,
+			"Account Id":     user.AccountId,
+			"Email":          user.Email,
+			"Line Manager":   user.LineManager,
+			"Team":           user.Team,
+		}
+		if recordType == userCsvRecord {
+			titleToValue["Active"] = s.formatBool(user.Active)
+			titleToValue["Last Seen"] = user.LastSeen
+			titleToValue["Archived"] = s.formatBool(user.Archived)
+		}
+
+		records = append(records, s.toRecord(titleToValue))
+	}
+
+	return records
+}
+
+func (s *CsvProjectConfigStorage) toRecord(titleToValue map[string]string) []string {
+	record := make([]string, len(projectConfigCsvTitles))
+	for i, title := range projectConfigCsvTitles {
+		record[i] = titleToValue[title]
+	}
+	return record
+}
+
+func (s *CsvProjectConfigStorage) formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
+
+func (s *CsvProjectConfigStorage) formatBool(value bool) string {
+	if value {
+		return yesValue
+	}
+	return noValue
+}
+
+// LoadForValidation is the same as Load, csv file cannot be read partially.
+func (s *CsvProjectConfigStorage) LoadForValidation() (*models.ProjectConfigWrapper, error) {
+	return s.Load()
+}
+
+// Validate does not check anything, csv file has no structure besides the one checked on loading.
+func (s *CsvProjectConfigStorage) Validate() ([]models.ProjectConfigIssue, error) {
+	return nil, nil
+}
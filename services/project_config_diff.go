@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"pm-report/models"
+	"text/tabwriter"
+)
+
+const (
+	addedProjectConfigChange       = "ADDED"
+	updatedProjectConfigChange     = "UPDATED"
+	inactiveProjectConfigChange    = "INACTIVE"
+	activeProjectConfigChange      = "ACTIVE"
+	missingRateProjectConfigChange = "MISSING RATE"
+	archivedProjectConfigChange    = "ARCHIVED"
+	restoredProjectConfigChange    = "RESTORED"
+)
+
+// getChanges compares project configs before and after synchronization,
+// users of the report without any rate (project, people registry or rate card) are listed too.
+func (s *ProjectConfigService) getChanges(projectConfigWrapper, updatedProjectConfigWrapper *models.ProjectConfigWrapper, report *models.Report) []models.ProjectConfigChange {
+	var changes []models.ProjectConfigChange
+
+	for _, updatedProjectConfig := range updatedProjectConfigWrapper.ProjectConfigs {
+		sheet := updatedProjectConfig.Key
+
+		projectConfig := projectConfigWrapper.Get(sheet)
+		if projectConfig == nil {
+			changes = append(changes, models.ProjectConfigChange{Sheet: sheet, Subject: "project", Change: addedProjectConfigChange})
+			projectConfig = &models.ProjectConfig{Key: sheet}
+		}
+
+		if updatedProjectConfig.Inactive != projectConfig.Inactive {
+			change := activeProjectConfigChange
+			if updatedProjectConfig.Inactive {
+				change = inactiveProjectConfigChange
+			}
+			changes = append(changes, models.ProjectConfigChange{Sheet: sheet, Subject: "project", Change: change})
+		}
+
+		changes = append(changes, s.getFieldChanges(sheet, "project", [][]string{
+			{"Display Name", projectConfig.DisplayName, updatedProjectConfig.DisplayName},
+			{"Owner", projectConfig.Owner, updatedProjectConfig.Owner},
+			{"Manager", projectConfig.Manager, updatedProjectConfig.Manager},
+		})...)
+
+		changes = append(changes, s.getUserChanges(sheet, projectConfig.Users, updatedProjectConfig.Users)...)
+	}
+
+	changes = append(changes, s.getUserChanges(peopleSheet, projectConfigWrapper.People, updatedProjectConfigWrapper.People)...)
+
+	for _, project := range report.Projects {
+		for _, user := range project.Users {
+			if user.BaseRate == 0 && len(user.RateHistory) == 0 && !user.RateFromCard {
+				changes = append(changes, models.ProjectConfigChange{Sheet: project.Key, Subject: user.Name, Change: missingRateProjectConfigChange})
+			}
+		}
+	}
+
+	return changes
+}
+
+func (s *ProjectConfigService) getUserChanges(sheet string, userConfigs, updatedUserConfigs []models.UserConfig) []models.ProjectConfigChange {
+	var changes []models.ProjectConfigChange
+	projectConfig := &models.ProjectConfig{Users: userConfigs} // users are matched the same way as in report
+
+	for _, updatedUserConfig := range updatedUserConfigs {
+		userConfig := projectConfig.GetUser(updatedUserConfig.AccountId, updatedUserConfig.Name)
+		if userConfig == nil {
+			changes = append(changes, models.ProjectConfigChange{Sheet: sheet, Subject: updatedUserConfig.Name, Change: addedProjectConfigChange})
+			continue
+		}
+
+		if updatedUserConfig.Archived != userConfig.Archived {
+			change := restoredProjectConfigChange
+			if updatedUserConfig.Archived {
+				change = archivedProjectConfigChange
+			}
+			changes = append(changes, models.ProjectConfigChange{Sheet: sheet, Subject: updatedUserConfig.Name, Change: change, Details: "Last Seen: " + updatedUserConfig.LastSeen})
+		}
+
+		changes = append(changes, s.getFieldChanges(sheet, updatedUserConfig.Name, [][]string{
+			{"Name", userConfig.Name, updatedUserConfig.Name},
+			{"Account Id", userConfig.AccountId, updatedUserConfig.AccountId},
+			{"Email", userConfig.Email, updatedUserConfig.Email},
+		})...)
+	}
+
+	return changes
+}
+
+// getFieldChanges compares fields given as title, old value and new value.
+func (s *ProjectConfigService) getFieldChanges(sheet, subject string, fields [][]string) []models.ProjectConfigChange {
+	var changes []models.ProjectConfigChange
+
+	for _, field := range fields {
+		if field[1] == field[2] {
+			continue
+		}
+
+		changes = append(changes, models.ProjectConfigChange{
+			Sheet:   sheet,
+			Subject: subject,
+			Change:  updatedProjectConfigChange,
+			Details: fmt.Sprintf("%s: %q -> %q", field[0], field[1], field[2]),
+		})
+	}
+
+	return changes
+}
+
+func (s *ProjectConfigService) printChanges(w io.Writer, changes []models.ProjectConfigChange) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, err := fmt.Fprintln(tw, "SHEET\tSUBJECT\tCHANGE\tDETAILS")
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		_, err = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", change.Sheet, change.Subject, change.Change, change.Details)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"pm-report/models"
+	"reflect"
+	"testing"
+)
+
+func TestProjectConfigServiceGetChanges(t *testing.T) {
+	wrapper := newTestProjectConfigWrapper()
+	wrapper.ProjectConfigs[1].Inactive = false
+	report := newTestReport(newTestUser("b2", "Bob", "", 25, 8), newTestUser("d4", "Dave", "", 0, 8))
+	report.Projects = append(report.Projects, models.Project{Key: "NEW"})
+
+	projectConfigService := NewProjectConfigService("", true, 3, true, nil)
+	changes := projectConfigService.getChanges(wrapper, projectConfigService.updateProjectConfigs(wrapper, report), report)
+
+	want := []models.ProjectConfigChange{
+		{Sheet: "ABC", Subject: "Dave", Change: addedProjectConfigChange},
+		{Sheet: "NEW", Subject: "project", Change: addedProjectConfigChange},
+		{Sheet: "XYZ", Subject: "project", Change: inactiveProjectConfigChange},
+		{Sheet: peopleSheet, Subject: "Dave", Change: addedProjectConfigChange},
+		{Sheet: "ABC", Subject: "Dave", Change: missingRateProjectConfigChange},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("changes = %+v, want %+v", changes, want)
+	}
+
+	var buffer bytes.Buffer
+	err := projectConfigService.printChanges(&buffer, want[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantOutput := "SHEET  SUBJECT  CHANGE  DETAILS\n" +
+		"ABC    Dave     ADDED   \n" +
+		"NEW    project  ADDED   \n"
+	if buffer.String() != wantOutput {
+		t.Fatalf("output = %q, want %q", buffer.String(), wantOutput)
+	}
+}
+
+func TestProjectConfigServiceSaveDryRunWritesNothing(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.xlsx")
+	wrapper := newTestProjectConfigWrapper()
+	err := NewExcelProjectConfigStorage(filePath).Save(wrapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := newTestReport(newTestUser("d4", "Dave", "", 0, 8))
+	err = NewProjectConfigService(filePath, true, 3, true, nil).Save(wrapper, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	savedData, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(savedData, data) {
+		t.Fatalf("%s is changed by dry run", filePath)
+	}
+}
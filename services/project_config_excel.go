@@ -0,0 +1,1378 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"os"
+	"pm-report/models"
+	"pm-report/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	peopleSheet         = "People"
+	rateCardSheet       = "Rate Card"
+	keyTitleValue       = "Key"
+	activeHeaderValue   = "Project Info"
+	inactiveHeaderValue = "Project Info (Inactive)"
+	activeTitleValue    = "Active"
+	lastSeenTitleValue  = "Last Seen"
+	archivedValue       = "Archived"
+	yesValue            = "Yes"
+	noValue             = "No"
+)
+
+var rateCardTitles = []string{"Position", "Rate", "Currency", "Project", "Client"}
+
+// ExcelProjectConfigStorage keeps project configs in a workbook: sheet per project, people registry and rate card.
+type ExcelProjectConfigStorage struct {
+	filePath         string
+	skipInvalidCells bool // rows with invalid rate or date are skipped instead of failing
+}
+
+func NewExcelProjectConfigStorage(filePath string) *ExcelProjectConfigStorage {
+	return &ExcelProjectConfigStorage{filePath: filePath}
+}
+
+// LoadForValidation reads the workbook skipping rows with invalid cells, they are reported by Validate.
+func (s *ExcelProjectConfigStorage) LoadForValidation() (*models.ProjectConfigWrapper, error) {
+	storage := &ExcelProjectConfigStorage{filePath: s.filePath, skipInvalidCells: true}
+	return storage.Load()
+}
+
+// Load reads project sheets, people registry and rate card, sheets added by users are skipped.
+func (s *ExcelProjectConfigStorage) Load() (*models.ProjectConfigWrapper, error) {
+	_, err := os.Stat(s.filePath)
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return &models.ProjectConfigWrapper{}, nil
+	}
+
+	f, err := excelize.OpenFile(s.filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	context := s.createContext()
+	var projectConfigs []models.ProjectConfig
+	var people []models.UserConfig
+	var rateCards []models.RateCardConfig
+
+	for _, sheet := range f.GetSheetList() {
+		if sheet == peopleSheet {
+			people, err = s.getUserConfigs(f, sheet, s.createPeopleContext())
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if sheet == rateCardSheet {
+			rateCards, err = s.getRateCards(f)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		keyTitle, err := f.GetCellValue(sheet, context.Project.KeyTitleCell)
+		if err != nil {
+			return nil, err
+		}
+		if keyTitle != keyTitleValue {
+			continue // sheet added by user
+		}
+
+		projectConfig, err := s.getProjectConfig(f, sheet, context)
+		if err != nil {
+			return nil, err
+		}
+		projectConfigs = append(projectConfigs, *projectConfig)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ProjectConfigWrapper{ProjectConfigs: projectConfigs, People: people, RateCards: rateCards}, nil
+}
+
+func (s *ExcelProjectConfigStorage) getProjectConfig(f *excelize.File, sheet string, context *models.ProjectConfigContext) (*models.ProjectConfig, error) {
+	displayName, err := f.GetCellValue(sheet, context.Project.DisplayNameValueCell)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := f.GetCellValue(sheet, context.Project.OwnerValueCell)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, err := f.GetCellValue(sheet, context.Project.ManagerValueCell)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := f.GetCellValue(sheet, context.Project.HeaderCell)
+	if err != nil {
+		return nil, err
+	}
+
+	userConfigs, err := s.getUserConfigs(f, sheet, context)
+	if err != nil {
+		return nil, err
+	}
+
+	projectConfig := models.ProjectConfig{
+		Key:         sheet,
+		DisplayName: displayName,
+		Owner:       owner,
+		Manager:     manager,
+		Users:       userConfigs,
+		Inactive:    header == inactiveHeaderValue,
+	}
+	return &projectConfig, nil
+}
+
+func (s *ExcelProjectConfigStorage) getUserConfigs(f *excelize.File, sheet string, context *models.ProjectConfigContext) ([]models.UserConfig, error) {
+	projectConfig := &models.ProjectConfig{Key: sheet}
+
+	context, err := s.resolveActivityColumns(f, sheet, context)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	rowIndex := 0
+	archived := false
+	for rows.Next() {
+		rowIndex++
+		if rowIndex <= context.User.HeaderRowIndex {
+			continue // skip
+		}
+
+		rowCols, err := rows.Columns(excelize.Options{RawCellValue: true}) // rate is read without currency format
+		if err != nil {
+			return nil, err
+		}
+
+		if rowCols == nil {
+			continue
+		}
+
+		userName := s.getColumnValue(rowCols, context.User.NameColumn)
+		if len(userName) == 0 { // required
+			continue
+		}
+
+		if s.isArchivedRow(rowCols, context) {
+			archived = true // users below are archived
+			continue
+		}
+		accountId := s.getColumnValue(rowCols, context.User.AccountIdColumn)
+
+		rate := 0.0
+		if rateValue := s.getColumnValue(rowCols, context.User.RateColumn); len(rateValue) > 0 {
+			rate, err = strconv.ParseFloat(rateValue, 64)
+			if err != nil && s.skipInvalidCells {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error: rate of %s in %s project config is not a number: %s", userName, sheet, rateValue)
+			}
+		}
+
+		effectiveFrom := ""
+		if effectiveFromValue := s.getColumnValue(rowCols, context.User.EffectiveFromColumn); len(effectiveFromValue) > 0 {
+			effectiveFrom, err = s.parseDate(effectiveFromValue)
+			if err != nil && s.skipInvalidCells {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error: effective date of %s in %s project config is not recognized: %s", userName, sheet, effectiveFromValue)
+			}
+		}
+
+		lastSeen := ""
+		if lastSeenValue := s.getColumnValue(rowCols, context.User.LastSeenColumn); len(lastSeenValue) > 0 {
+			lastSeen, err = s.parseDate(lastSeenValue)
+			if err != nil && s.skipInvalidCells {
+				lastSeen = ""
+			} else if err != nil {
+				return nil, fmt.Errorf("error: last seen date of %s in %s project config is not recognized: %s", userName, sheet, lastSeenValue)
+			}
+		}
+
+		projectConfig.AddUserRow(models.UserConfig{
+			AccountId: accountId,
+			Email:     s.getColumnValue(rowCols, context.User.EmailColumn),
+			Name:      userName,
+			Position:  s.getColumnValue(rowCols, context.User.PositionColumn),
+			Currency:  strings.ToUpper(strings.TrimSpace(s.getColumnValue(rowCols, context.User.CurrencyColumn))),
+
+			LineManager: s.getColumnValue(rowCols, context.User.LineManagerColumn),
+			Team:        s.getColumnValue(rowCols, context.User.TeamColumn),
+
+			Active:   s.getColumnValue(rowCols, context.User.ActiveColumn) == yesValue,
+			LastSeen: lastSeen,
+			Archived: archived,
+		}, models.RateConfig{EffectiveFrom: effectiveFrom, Rate: rate})
+	}
+
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
+	return projectConfig.Users, nil
+}
+
+func (s *ExcelProjectConfigStorage) getRateCards(f *excelize.File) ([]models.RateCardConfig, error) {
+	var rateCards []models.RateCardConfig
+
+	rows, err := f.Rows(rateCardSheet)
+	if err != nil {
+		return nil, err
+	}
+
+	rowIndex := 0
+	for rows.Next() {
+		rowIndex++
+		if rowIndex <= 1 {
+			continue // skip header
+		}
+
+		rowCols, err := rows.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rowCols) == 0 || len(strings.TrimSpace(rowCols[0])) == 0 { // position is required
+			continue
+		}
+		position := strings.TrimSpace(rowCols[0])
+
+		rate := 0.0
+		if len(rowCols) > 1 && len(rowCols[1]) > 0 {
+			rate, err = strconv.ParseFloat(rowCols[1], 64)
+			if err != nil && s.skipInvalidCells {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error: rate of %s in rate card is not a number: %s", position, rowCols[1])
+			}
+		}
+
+		currency := ""
+		if len(rowCols) > 2 {
+			currency = strings.ToUpper(strings.TrimSpace(rowCols[2]))
+		}
+
+		project := ""
+		if len(rowCols) > 3 {
+			project = strings.TrimSpace(rowCols[3])
+		}
+
+		client := ""
+		if len(rowCols) > 4 {
+			client = strings.TrimSpace(rowCols[4])
+		}
+
+		rateCards = append(rateCards, models.RateCardConfig{
+			Position: position,
+			Rate:     rate,
+			Currency: currency,
+			Project:  project,
+			Client:   client,
+		})
+	}
+
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
+	return rateCards, nil
+}
+
+// getColumnValue returns value of the column (e.g. "C"), empty if the column is not defined or the cell is empty.
+func (s *ExcelProjectConfigStorage) getColumnValue(rowCols []string, column string) string {
+	if len(column) == 0 {
+		return ""
+	}
+
+	index, err := excelize.ColumnNameToNumber(column)
+	if err != nil || index > len(rowCols) {
+		return ""
+	}
+	return rowCols[index-1]
+}
+
+// parseDate accepts date typed as text (yyyy-mm-dd) or excel date cell (serial number).
+func (s *ExcelProjectConfigStorage) parseDate(value string) (string, error) {
+	date, err := time.Parse(effortDateFormat, strings.TrimSpace(value))
+	if err == nil {
+		return date.Format(effortDateFormat), nil
+	}
+
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return "", err
+	}
+	date, err = excelize.ExcelDateToTime(serial, false)
+	if err != nil {
+		return "", err
+	}
+	return date.Format(effortDateFormat), nil
+}
+
+// Save updates existing workbook in place: known cells are refreshed and new users are appended,
+// so that extra sheets, columns and formatting added by users are preserved. Missing sheets are created.
+func (s *ExcelProjectConfigStorage) Save(projectConfigWrapper *models.ProjectConfigWrapper) error {
+	f, err := s.createOrOpenFile()
+	if err != nil {
+		return err
+	}
+
+	context := s.createContext()
+
+	if f.GetSheetIndex(peopleSheet) == -1 {
+		err = s.fillPeopleSheet(f, projectConfigWrapper.People)
+	} else {
+		err = s.updateUsers(f, peopleSheet, s.createPeopleContext(), projectConfigWrapper.People)
+	}
+	if err != nil {
+		return err
+	}
+
+	if f.GetSheetIndex(rateCardSheet) == -1 {
+		err = s.fillRateCardSheet(f, projectConfigWrapper.RateCards)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, projectConfig := range projectConfigWrapper.ProjectConfigs {
+		if f.GetSheetIndex(projectConfig.Key) == -1 {
+			s.createSheet(f, projectConfig.Key)
+
+			err = s.fillProjectInfo(f, projectConfig.Key, context, &projectConfig)
+			if err != nil {
+				return err
+			}
+
+			err = s.fillUsersHeader(f, projectConfig.Key, context, &projectConfig)
+			if err != nil {
+				return err
+			}
+
+			err = s.fillUsersBody(f, projectConfig.Key, context, &projectConfig)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = s.updateProjectInfo(f, projectConfig.Key, context, &projectConfig)
+		if err != nil {
+			return err
+		}
+
+		err = s.updateUsers(f, projectConfig.Key, context, projectConfig.Users)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = f.SaveAs(s.filePath)
+	if err != nil {
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ExcelProjectConfigStorage) createOrOpenFile() (*excelize.File, error) {
+	_, err := os.Stat(s.filePath)
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return excelize.NewFile(), nil
+	}
+
+	return excelize.OpenFile(s.filePath)
+}
+
+// updateProjectInfo refreshes project header and info values which differ, cell styles are kept.
+func (s *ExcelProjectConfigStorage) updateProjectInfo(f *excelize.File, sheet string, context *models.ProjectConfigContext, projectConfig *models.ProjectConfig) error {
+	cellToValue := [][]string{
+		{context.Project.HeaderCell, s.getHeaderValue(projectConfig)},
+		{context.Project.DisplayNameValueCell, projectConfig.DisplayName},
+		{context.Project.OwnerValueCell, projectConfig.Owner},
+		{context.Project.ManagerValueCell, projectConfig.Manager},
+	}
+
+	for _, pair := range cellToValue {
+		err := s.updateCellValue(f, sheet, pair[0], pair[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ExcelProjectConfigStorage) getHeaderValue(projectConfig *models.ProjectConfig) string {
+	if projectConfig.Inactive {
+		return inactiveHeaderValue
+	}
+	return activeHeaderValue
+}
+
+// updateUsers refreshes name, account id, email, activity and last seen date of existing user rows
+// and appends new users (above archived section), other cells (including user columns and rate history rows)
+// are left untouched. Missing titles of known columns are added to the header (e.g. for sheets created by older versions).
+// Rows of archived users are moved below archived section title and restored users are moved back.
+func (s *ExcelProjectConfigStorage) updateUsers(f *excelize.File, sheet string, context *models.ProjectConfigContext, users []models.UserConfig) error {
+	context, err := s.resolveActivityColumns(f, sheet, context)
+	if err != nil {
+		return err
+	}
+
+	err = s.updateUsersHeader(f, sheet, context)
+	if err != nil {
+		return err
+	}
+
+	rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return err
+	}
+
+	existingUsers := map[int]bool{} // indexes of users already present in the sheet
+	lastRowIndex := context.User.HeaderRowIndex
+	archivedRowIndex := s.findArchivedRowIndex(rows, context)
+
+	for rowIndex := context.User.HeaderRowIndex + 1; rowIndex <= len(rows); rowIndex++ {
+		rowCols := rows[rowIndex-1]
+		name := s.getColumnValue(rowCols, context.User.NameColumn)
+		if len(name) == 0 || rowIndex == archivedRowIndex {
+			continue
+		}
+		lastRowIndex = rowIndex
+
+		userIndex := s.findUserIndex(users, s.getColumnValue(rowCols, context.User.AccountIdColumn), name)
+		if userIndex == -1 {
+			continue
+		}
+		existingUsers[userIndex] = true
+
+		user := users[userIndex]
+		cellToValue := [][]string{
+			{context.User.NameColumn, user.Name},
+			{context.User.AccountIdColumn, user.AccountId},
+			{context.User.EmailColumn, user.Email},
+		}
+		if len(context.User.ActiveColumn) > 0 {
+			cellToValue = append(cellToValue, []string{context.User.ActiveColumn, s.getActiveValue(user)}, []string{context.User.LastSeenColumn, user.LastSeen})
+		}
+		for _, pair := range cellToValue {
+			err = s.updateCellValue(f, sheet, pair[0]+strconv.Itoa(rowIndex), pair[1])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	conditionalFormat, err := s.getConditionalFormat(f)
+	if err != nil {
+		return err
+	}
+
+	for i, user := range users {
+		if existingUsers[i] {
+			continue
+		}
+
+		for _, rateConfig := range user.GetRateConfigs() {
+			rowIndex := lastRowIndex + 1
+			if archivedRowIndex > 0 {
+				// new users are inserted above archived section
+				rowIndex = archivedRowIndex
+				archivedRowIndex++
+
+				err = f.InsertRow(sheet, rowIndex)
+				if err != nil {
+					return err
+				}
+			}
+			lastRowIndex++
+
+			err = s.fillUserRow(f, sheet, context, strconv.Itoa(rowIndex), user, rateConfig, *conditionalFormat)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(context.User.ActiveColumn) == 0 {
+		return nil
+	}
+	return s.moveArchivedUsers(f, sheet, context, users)
+}
+
+// moveArchivedUsers moves rows of archived users to the end of archived section (title is added if it is missing)
+// and rows of restored users to the end of current users. The sheet is read once, all rows of a user
+// (e.g. rate history rows) are moved as a group with all their cells keeping their order.
+func (s *ExcelProjectConfigStorage) moveArchivedUsers(f *excelize.File, sheet string, context *models.ProjectConfigContext, users []models.UserConfig) error {
+	rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return err
+	}
+	archivedRowIndex := s.findArchivedRowIndex(rows, context)
+
+	// rows to move in sheet order, identified by their original indexes
+	var archivedRows, restoredRows []int
+	for rowIndex := context.User.HeaderRowIndex + 1; rowIndex <= len(rows); rowIndex++ {
+		rowCols := rows[rowIndex-1]
+		name := s.getColumnValue(rowCols, context.User.NameColumn)
+		if len(name) == 0 || rowIndex == archivedRowIndex {
+			continue
+		}
+
+		userIndex := s.findUserIndex(users, s.getColumnValue(rowCols, context.User.AccountIdColumn), name)
+		if userIndex == -1 {
+			continue
+		}
+
+		isArchivedRow := archivedRowIndex > 0 && rowIndex > archivedRowIndex
+		switch {
+		case users[userIndex].Archived && !isArchivedRow:
+			archivedRows = append(archivedRows, rowIndex)
+		case !users[userIndex].Archived && isArchivedRow:
+			restoredRows = append(restoredRows, rowIndex)
+		}
+	}
+	archivedRows = s.groupRowsByUser(rows, context, users, archivedRows)
+	restoredRows = s.groupRowsByUser(rows, context, users, restoredRows)
+
+	if len(archivedRows) == 0 && len(restoredRows) == 0 {
+		return nil
+	}
+
+	// order keeps original index of the row at each position after header
+	lastRowIndex := len(rows)
+	if archivedRowIndex == 0 {
+		lastRowIndex++
+		archivedRowIndex = lastRowIndex
+		err = s.fillArchivedRow(f, sheet, context, strconv.Itoa(archivedRowIndex))
+		if err != nil {
+			return err
+		}
+	}
+	order := make([]int, 0, lastRowIndex-context.User.HeaderRowIndex)
+	for rowIndex := context.User.HeaderRowIndex + 1; rowIndex <= lastRowIndex; rowIndex++ {
+		order = append(order, rowIndex)
+	}
+	for _, rowIndex := range restoredRows {
+		order, err = s.moveRow(f, sheet, context, order, s.getRowPosition(context, order, rowIndex), s.getRowPosition(context, order, archivedRowIndex))
+		if err != nil {
+			return err
+		}
+	}
+	for _, rowIndex := range archivedRows {
+		order, err = s.moveRow(f, sheet, context, order, s.getRowPosition(context, order, rowIndex), context.User.HeaderRowIndex+len(order)+1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// groupRowsByUser orders rows so that rows of the same user follow each other in sheet order of users.
+func (s *ExcelProjectConfigStorage) groupRowsByUser(rows [][]string, context *models.ProjectConfigContext, users []models.UserConfig, rowIndexes []int) []int {
+	var userIndexes []int
+	userIndexToRows := map[int][]int{}
+	for _, rowIndex := range rowIndexes {
+		rowCols := rows[rowIndex-1]
+		userIndex := s.findUserIndex(users, s.getColumnValue(rowCols, context.User.AccountIdColumn), s.getColumnValue(rowCols, context.User.NameColumn))
+		if _, ok := userIndexToRows[userIndex]; !ok {
+			userIndexes = append(userIndexes, userIndex)
+		}
+		userIndexToRows[userIndex] = append(userIndexToRows[userIndex], rowIndex)
+	}
+
+	grouped := make([]int, 0, len(rowIndexes))
+	for _, userIndex := range userIndexes {
+		grouped = append(grouped, userIndexToRows[userIndex]...)
+	}
+	return grouped
+}
+
+// getRowPosition finds current index of the row by its original index.
+func (s *ExcelProjectConfigStorage) getRowPosition(context *models.ProjectConfigContext, order []int, originalRowIndex int) int {
+	for i, rowIndex := range order {
+		if rowIndex == originalRowIndex {
+			return context.User.HeaderRowIndex + 1 + i
+		}
+	}
+	return 0
+}
+
+// moveRow moves row with all its cells before the target row (target can be right after the last row),
+// order of original row indexes is updated the same way.
+func (s *ExcelProjectConfigStorage) moveRow(f *excelize.File, sheet string, context *models.ProjectConfigContext, order []int, fromRowIndex, toRowIndex int) ([]int, error) {
+	if fromRowIndex == toRowIndex || fromRowIndex+1 == toRowIndex {
+		return order, nil // already in place
+	}
+
+	err := f.DuplicateRowTo(sheet, fromRowIndex, toRowIndex)
+	if err != nil {
+		return nil, err
+	}
+	removedRowIndex := fromRowIndex
+	if toRowIndex < fromRowIndex {
+		removedRowIndex++ // original row is shifted down by inserted copy
+	}
+	err = f.RemoveRow(sheet, removedRowIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	from := fromRowIndex - context.User.HeaderRowIndex - 1
+	to := toRowIndex - context.User.HeaderRowIndex - 1
+	originalRowIndex := order[from]
+	order = append(order[:from], order[from+1:]...)
+	if to > from {
+		to--
+	}
+	order = append(order[:to], append([]int{originalRowIndex}, order[to:]...)...)
+
+	return order, nil
+}
+
+// findArchivedRowIndex finds row of archived section title, 0 if there is no such section.
+func (s *ExcelProjectConfigStorage) findArchivedRowIndex(rows [][]string, context *models.ProjectConfigContext) int {
+	for rowIndex := context.User.HeaderRowIndex + 1; rowIndex <= len(rows); rowIndex++ {
+		if s.isArchivedRow(rows[rowIndex-1], context) {
+			return rowIndex
+		}
+	}
+	return 0
+}
+
+// isArchivedRow checks whether the row is archived section title: it has archived value both in name
+// and activity columns, so user named "Archived" is not taken for the title.
+func (s *ExcelProjectConfigStorage) isArchivedRow(rowCols []string, context *models.ProjectConfigContext) bool {
+	return len(context.User.ActiveColumn) > 0 &&
+		s.getColumnValue(rowCols, context.User.NameColumn) == archivedValue &&
+		s.getColumnValue(rowCols, context.User.ActiveColumn) == archivedValue
+}
+
+func (s *ExcelProjectConfigStorage) fillArchivedRow(f *excelize.File, sheet string, context *models.ProjectConfigContext, rowIndex string) error {
+	style, err := s.createHeaderStyle(f)
+	if err != nil {
+		return err
+	}
+	err = f.SetCellStyle(sheet, context.User.NameColumn+rowIndex, context.User.LastSeenColumn+rowIndex, style)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.NameColumn+rowIndex, archivedValue)
+	if err != nil {
+		return err
+	}
+	return f.SetCellValue(sheet, context.User.ActiveColumn+rowIndex, archivedValue)
+}
+
+func (s *ExcelProjectConfigStorage) getActiveValue(user models.UserConfig) string {
+	if user.Active {
+		return yesValue
+	}
+	return noValue
+}
+
+// findUserIndex matches sheet row with user by account id, rows without account id are matched by name.
+func (s *ExcelProjectConfigStorage) findUserIndex(users []models.UserConfig, accountId, name string) int {
+	for i, user := range users {
+		if len(accountId) > 0 && user.AccountId == accountId {
+			return i
+		}
+		if len(accountId) == 0 && user.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *ExcelProjectConfigStorage) updateUsersHeader(f *excelize.File, sheet string, context *models.ProjectConfigContext) error {
+	rowIndex := strconv.Itoa(context.User.HeaderRowIndex)
+
+	style, err := s.createHeaderStyle(f)
+	if err != nil {
+		return err
+	}
+
+	for _, pair := range s.getUserTitles(context) {
+		title, err := f.GetCellValue(sheet, pair[0]+rowIndex)
+		if err != nil {
+			return err
+		}
+		if len(title) > 0 {
+			continue
+		}
+
+		err = f.SetCellValue(sheet, pair[0]+rowIndex, pair[1])
+		if err != nil {
+			return err
+		}
+		err = f.SetCellStyle(sheet, pair[0]+rowIndex, pair[0]+rowIndex, style)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveActivityColumns finds activity and last seen columns of existing sheet by their titles, so that columns
+// added by users (e.g. notes in the first free columns of sheets created by older versions) are never overwritten.
+// Missing columns are placed after the last titled column.
+func (s *ExcelProjectConfigStorage) resolveActivityColumns(f *excelize.File, sheet string, context *models.ProjectConfigContext) (*models.ProjectConfigContext, error) {
+	if len(context.User.ActiveColumn) == 0 {
+		return context, nil
+	}
+
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var header []string
+	if len(rows) >= context.User.HeaderRowIndex {
+		header = rows[context.User.HeaderRowIndex-1]
+	}
+
+	resolved := *context
+	resolved.User.ActiveColumn = ""
+	resolved.User.LastSeenColumn = ""
+
+	// columns up to the effective date are always known, even if their titles are missing
+	lastColumnIndex, err := excelize.ColumnNameToNumber(context.User.EffectiveFromColumn)
+	if err != nil {
+		return nil, err
+	}
+	for i, title := range header {
+		if len(strings.TrimSpace(title)) == 0 {
+			continue
+		}
+		if i+1 > lastColumnIndex {
+			lastColumnIndex = i + 1
+		}
+
+		column, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return nil, err
+		}
+		switch strings.TrimSpace(title) {
+		case activeTitleValue:
+			resolved.User.ActiveColumn = column
+		case lastSeenTitleValue:
+			resolved.User.LastSeenColumn = column
+		}
+	}
+
+	for _, column := range []*string{&resolved.User.ActiveColumn, &resolved.User.LastSeenColumn} {
+		if len(*column) > 0 {
+			continue
+		}
+		lastColumnIndex++
+		*column, err = excelize.ColumnNumberToName(lastColumnIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &resolved, nil
+}
+
+// getUserTitles lists known columns of users table with their titles.
+func (s *ExcelProjectConfigStorage) getUserTitles(context *models.ProjectConfigContext) [][]string {
+	columnToTitle := [][]string{
+		{context.User.NameColumn, "Name"},
+		{context.User.PositionColumn, "Position"},
+		{context.User.RateColumn, "Rate"},
+		{context.User.AccountIdColumn, "Account Id"},
+		{context.User.EmailColumn, "Email"},
+		{context.User.CurrencyColumn, "Currency"},
+		{context.User.EffectiveFromColumn, "Effective From"},
+	}
+	if len(context.User.TeamColumn) > 0 {
+		columnToTitle = append(columnToTitle, []string{context.User.LineManagerColumn, "Line Manager"}, []string{context.User.TeamColumn, "Team"})
+	}
+	if len(context.User.ActiveColumn) > 0 {
+		columnToTitle = append(columnToTitle, []string{context.User.ActiveColumn, activeTitleValue}, []string{context.User.LastSeenColumn, lastSeenTitleValue})
+	}
+	return columnToTitle
+}
+
+// updateCellValue sets value only if it differs, so that untouched cells keep their types.
+func (s *ExcelProjectConfigStorage) updateCellValue(f *excelize.File, sheet, cell, value string) error {
+	currentValue, err := f.GetCellValue(sheet, cell)
+	if err != nil {
+		return err
+	}
+	if currentValue == value {
+		return nil
+	}
+	return f.SetCellValue(sheet, cell, value)
+}
+
+func (s *ExcelProjectConfigStorage) createSheet(f *excelize.File, sheet string) {
+	f.NewSheet(sheet)
+
+	if f.GetSheetIndex("Sheet1") != -1 {
+		f.DeleteSheet("Sheet1")
+	}
+}
+
+func (s *ExcelProjectConfigStorage) fillProjectInfo(f *excelize.File, sheet string, context *models.ProjectConfigContext, projectConfig *models.ProjectConfig) error {
+	err := f.MergeCell(sheet, "A1", "C1")
+	if err != nil {
+		return err
+	}
+	alignment := excelize.Alignment{Horizontal: "center"}
+	borders := []excelize.Border{
+		{Type: "top", Color: "#000000", Style: 1},
+		{Type: "left", Color: "#000000", Style: 1},
+		{Type: "bottom", Color: "#000000", Style: 1},
+		{Type: "right", Color: "#000000", Style: 1},
+	}
+	headerFont := excelize.Font{Color: "#ffffff", Bold: true}
+	fill := excelize.Fill{Color: []string{"#009a00"}, Type: "pattern", Pattern: 1}
+	style, err := f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &headerFont, Border: borders, Fill: fill})
+	if err != nil {
+		return err
+	}
+	err = f.SetCellStyle(sheet, context.Project.HeaderCell, context.Project.HeaderCell, style)
+	if err != nil {
+		return err
+	}
+	err = f.SetCellValue(sheet, context.Project.HeaderCell, s.getHeaderValue(projectConfig))
+	if err != nil {
+		return err
+	}
+
+	col, row, err := excelize.CellNameToCoordinates(context.Project.KeyValueCell)
+	if err != nil {
+		return err
+	}
+	cell, err := excelize.CoordinatesToCellName(col+1, row)
+	if err != nil {
+		return err
+	}
+	err = f.MergeCell(sheet, context.Project.KeyValueCell, cell)
+	if err != nil {
+		return err
+	}
+	err = f.SetCellValue(sheet, context.Project.KeyTitleCell, keyTitleValue)
+	if err != nil {
+		return err
+	}
+	err = f.SetCellValue(sheet, context.Project.KeyValueCell, sheet)
+	if err != nil {
+		return err
+	}
+
+	col, row, err = excelize.CellNameToCoordinates(context.Project.DisplayNameValueCell)
+	if err != nil {
+		return err
+	}
+	cell, err = excelize.CoordinatesToCellName(col+1, row)
+	if err != nil {
+		return err
+	}
+	err = f.MergeCell(sheet, context.Project.DisplayNameValueCell, cell)
+	if err != nil {
+		return err
+	}
+	err = f.SetCellValue(sheet, context.Project.DisplayNameTitleCell, "Display Name")
+	if err != nil {
+		return err
+	}
+	err = f.SetCellValue(sheet, context.Project.DisplayNameValueCell, projectConfig.DisplayName)
+	if err != nil {
+		return err
+	}
+
+	col, row, err = excelize.CellNameToCoordinates(context.Project.OwnerValueCell)
+	if err != nil {
+		return err
+	}
+	cell, err = excelize.CoordinatesToCellName(col+1, row)
+	if err != nil {
+		return err
+	}
+	err = f.MergeCell(sheet, context.Project.OwnerValueCell, cell)
+	if err != nil {
+		return err
+	}
+	err = f.SetCellValue(sheet, context.Project.OwnerTitleCell, "Owner")
+	if err != nil {
+		return err
+	}
+	err = f.SetCellValue(sheet, context.Project.OwnerValueCell, projectConfig.Owner)
+	if err != nil {
+		return err
+	}
+
+	col, row, err = excelize.CellNameToCoordinates(context.Project.ManagerValueCell)
+	if err != nil {
+		return err
+	}
+	cell, err = excelize.CoordinatesToCellName(col+1, row)
+	if err != nil {
+		return err
+	}
+	err = f.MergeCell(sheet, context.Project.ManagerValueCell, cell)
+	if err != nil {
+		return err
+	}
+	err = f.SetCellValue(sheet, context.Project.ManagerTitleCell, "Manager")
+	if err != nil {
+		return err
+	}
+	err = f.SetCellValue(sheet, context.Project.ManagerValueCell, projectConfig.Manager)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ExcelProjectConfigStorage) fillUsersHeader(f *excelize.File, sheet string, context *models.ProjectConfigContext, projectConfig *models.ProjectConfig) error {
+	err := f.SetColWidth(sheet, context.User.NameColumn, context.User.NameColumn, 30)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(sheet, context.User.PositionColumn, context.User.PositionColumn, 30)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(sheet, context.User.RateColumn, context.User.RateColumn, 10)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(sheet, context.User.AccountIdColumn, context.User.AccountIdColumn, 30)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(sheet, context.User.EmailColumn, context.User.EmailColumn, 30)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(sheet, context.User.CurrencyColumn, context.User.CurrencyColumn, 10)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(sheet, context.User.EffectiveFromColumn, context.User.EffectiveFromColumn, 15)
+	if err != nil {
+		return err
+	}
+
+	lastColumn := context.User.EffectiveFromColumn
+	if len(context.User.TeamColumn) > 0 {
+		err = f.SetColWidth(sheet, context.User.LineManagerColumn, context.User.LineManagerColumn, 30)
+		if err != nil {
+			return err
+		}
+
+		err = f.SetColWidth(sheet, context.User.TeamColumn, context.User.TeamColumn, 20)
+		if err != nil {
+			return err
+		}
+
+		lastColumn = context.User.TeamColumn
+	}
+	if len(context.User.ActiveColumn) > 0 {
+		err = f.SetColWidth(sheet, context.User.ActiveColumn, context.User.ActiveColumn, 10)
+		if err != nil {
+			return err
+		}
+
+		err = f.SetColWidth(sheet, context.User.LastSeenColumn, context.User.LastSeenColumn, 15)
+		if err != nil {
+			return err
+		}
+
+		lastColumn = context.User.LastSeenColumn
+	}
+
+	style, err := f.NewStyle(&excelize.Style{NumFmt: 177})
+	if err != nil {
+		return err
+	}
+	err = f.SetColStyle(sheet, context.User.RateColumn, style)
+	if err != nil {
+		return err
+	}
+
+	if context.User.HeaderRowIndex > 1 {
+		rowIndex := strconv.Itoa(context.User.HeaderRowIndex - 1)
+
+		err = f.MergeCell(sheet, context.User.NameColumn+rowIndex, lastColumn+rowIndex)
+		if err != nil {
+			return err
+		}
+	}
+
+	rowIndex := strconv.Itoa(context.User.HeaderRowIndex)
+
+	style, err = s.createHeaderStyle(f)
+	if err != nil {
+		return err
+	}
+	err = f.SetCellStyle(sheet, context.User.NameColumn+rowIndex, lastColumn+rowIndex, style)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.NameColumn+rowIndex, "Name")
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.PositionColumn+rowIndex, "Position")
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.RateColumn+rowIndex, "Rate")
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.AccountIdColumn+rowIndex, "Account Id")
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.EmailColumn+rowIndex, "Email")
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.CurrencyColumn+rowIndex, "Currency")
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.EffectiveFromColumn+rowIndex, "Effective From")
+	if err != nil {
+		return err
+	}
+
+	if len(context.User.TeamColumn) > 0 {
+		err = f.SetCellValue(sheet, context.User.LineManagerColumn+rowIndex, "Line Manager")
+		if err != nil {
+			return err
+		}
+
+		err = f.SetCellValue(sheet, context.User.TeamColumn+rowIndex, "Team")
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(context.User.ActiveColumn) > 0 {
+		err = f.SetCellValue(sheet, context.User.ActiveColumn+rowIndex, activeTitleValue)
+		if err != nil {
+			return err
+		}
+
+		err = f.SetCellValue(sheet, context.User.LastSeenColumn+rowIndex, lastSeenTitleValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ExcelProjectConfigStorage) fillUsersBody(f *excelize.File, sheet string, context *models.ProjectConfigContext, projectConfig *models.ProjectConfig) error {
+	lastRowIndex := context.User.HeaderRowIndex
+
+	users := make([]models.UserConfig, len(projectConfig.Users))
+	copy(users, projectConfig.Users)
+	sort.SliceStable(users, func(i, j int) bool {
+		if users[i].Archived != users[j].Archived {
+			return !users[i].Archived // archived users go last
+		}
+		return users[i].Name < users[j].Name
+	})
+
+	conditionalFormat, err := s.getConditionalFormat(f)
+	if err != nil {
+		return err
+	}
+
+	archived := false
+	for _, user := range users {
+		if user.Archived && !archived && len(context.User.ActiveColumn) > 0 {
+			archived = true
+			lastRowIndex++
+
+			err = s.fillArchivedRow(f, sheet, context, strconv.Itoa(lastRowIndex))
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, rateConfig := range user.GetRateConfigs() {
+			lastRowIndex++
+
+			err = s.fillUserRow(f, sheet, context, strconv.Itoa(lastRowIndex), user, rateConfig, *conditionalFormat)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *ExcelProjectConfigStorage) fillUserRow(f *excelize.File, sheet string, context *models.ProjectConfigContext, rowIndex string, user models.UserConfig, rateConfig models.RateConfig, conditionalFormat string) error {
+	err := f.SetConditionalFormat(sheet, context.User.RateColumn+rowIndex, conditionalFormat)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.NameColumn+rowIndex, user.Name)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.PositionColumn+rowIndex, user.Position)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.RateColumn+rowIndex, rateConfig.Rate)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.AccountIdColumn+rowIndex, user.AccountId)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.EmailColumn+rowIndex, user.Email)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, context.User.CurrencyColumn+rowIndex, user.Currency)
+	if err != nil {
+		return err
+	}
+
+	if len(user.Currency) > 0 {
+		style, err := f.NewStyle(&excelize.Style{NumFmt: 177, CustomNumFmt: utils.ToCurrencyNumFmt(user.Currency)})
+		if err != nil {
+			return err
+		}
+		err = f.SetCellStyle(sheet, context.User.RateColumn+rowIndex, context.User.RateColumn+rowIndex, style)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = f.SetCellValue(sheet, context.User.EffectiveFromColumn+rowIndex, rateConfig.EffectiveFrom)
+	if err != nil {
+		return err
+	}
+
+	if len(context.User.TeamColumn) > 0 {
+		err = f.SetCellValue(sheet, context.User.LineManagerColumn+rowIndex, user.LineManager)
+		if err != nil {
+			return err
+		}
+
+		err = f.SetCellValue(sheet, context.User.TeamColumn+rowIndex, user.Team)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(context.User.ActiveColumn) > 0 {
+		err = f.SetCellValue(sheet, context.User.ActiveColumn+rowIndex, s.getActiveValue(user))
+		if err != nil {
+			return err
+		}
+
+		err = f.SetCellValue(sheet, context.User.LastSeenColumn+rowIndex, user.LastSeen)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fillPeopleSheet writes people registry, it has the same users table as project sheets plus line manager and team.
+func (s *ExcelProjectConfigStorage) fillPeopleSheet(f *excelize.File, people []models.UserConfig) error {
+	context := s.createPeopleContext()
+	projectConfig := &models.ProjectConfig{Key: peopleSheet, Users: people}
+
+	s.createSheet(f, peopleSheet)
+
+	err := s.fillUsersHeader(f, peopleSheet, context, projectConfig)
+	if err != nil {
+		return err
+	}
+
+	return s.fillUsersBody(f, peopleSheet, context, projectConfig)
+}
+
+// fillRateCardSheet writes rate card, empty project and client make the rate general.
+func (s *ExcelProjectConfigStorage) fillRateCardSheet(f *excelize.File, rateCards []models.RateCardConfig) error {
+	s.createSheet(f, rateCardSheet)
+
+	widths := []float64{30, 10, 10, 15, 30}
+	for i, title := range rateCardTitles {
+		col, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return err
+		}
+
+		err = f.SetColWidth(rateCardSheet, col, col, widths[i])
+		if err != nil {
+			return err
+		}
+
+		err = f.SetCellValue(rateCardSheet, col+"1", title)
+		if err != nil {
+			return err
+		}
+	}
+
+	style, err := s.createHeaderStyle(f)
+	if err != nil {
+		return err
+	}
+	err = f.SetCellStyle(rateCardSheet, "A1", "E1", style)
+	if err != nil {
+		return err
+	}
+
+	for i, rateCard := range rateCards {
+		rowIndex := strconv.Itoa(i + 2)
+
+		err = f.SetSheetRow(rateCardSheet, "A"+rowIndex, &[]interface{}{rateCard.Position, rateCard.Rate, rateCard.Currency, rateCard.Project, rateCard.Client})
+		if err != nil {
+			return err
+		}
+
+		style, err = f.NewStyle(&excelize.Style{NumFmt: 177, CustomNumFmt: utils.ToCurrencyNumFmt(rateCard.Currency)})
+		if err != nil {
+			return err
+		}
+		err = f.SetCellStyle(rateCardSheet, "B"+rowIndex, "B"+rowIndex, style)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ExcelProjectConfigStorage) createHeaderStyle(f *excelize.File) (int, error) {
+	alignment := excelize.Alignment{Horizontal: "center"}
+	borders := []excelize.Border{
+		{Type: "top", Color: "#000000", Style: 1},
+		{Type: "left", Color: "#000000", Style: 1},
+		{Type: "bottom", Color: "#000000", Style: 1},
+		{Type: "right", Color: "#000000", Style: 1},
+	}
+	headerFont := excelize.Font{Color: "#ffffff", Bold: true}
+	fill := excelize.Fill{Color: []string{"#009a00"}, Type: "pattern", Pattern: 1}
+	return f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &headerFont, Border: borders, Fill: fill})
+}
+
+func (s *ExcelProjectConfigStorage) createContext() *models.ProjectConfigContext {
+	return &models.ProjectConfigContext{
+		Project: models.InfoProjectConfigContext{
+			HeaderCell: "A1",
+
+			KeyTitleCell: "A2",
+			KeyValueCell: "B2",
+
+			DisplayNameTitleCell: "A3",
+			DisplayNameValueCell: "B3",
+
+			OwnerTitleCell: "A4",
+			OwnerValueCell: "B4",
+
+			ManagerTitleCell: "A5",
+			ManagerValueCell: "B5",
+		},
+		User: models.UserProjectConfigContext{
+			HeaderRowIndex:      7,
+			NameColumn:          "A",
+			PositionColumn:      "B",
+			RateColumn:          "C",
+			AccountIdColumn:     "D",
+			EmailColumn:         "E",
+			CurrencyColumn:      "F",
+			EffectiveFromColumn: "G",
+			ActiveColumn:        "H",
+			LastSeenColumn:      "I",
+		},
+	}
+}
+
+func (s *ExcelProjectConfigStorage) createPeopleContext() *models.ProjectConfigContext {
+	context := s.createContext()
+	context.User.HeaderRowIndex = 1
+	context.User.LineManagerColumn = "H"
+	context.User.TeamColumn = "I"
+	context.User.ActiveColumn = ""
+	context.User.LastSeenColumn = ""
+	return context
+}
+
+func (s *ExcelProjectConfigStorage) getConditionalFormat(f *excelize.File) (*string, error) {
+	format, err := f.NewConditionalStyle(`{
+		"font": {
+			"color": "#9A0511"
+		},
+		"fill": {
+			"type": "pattern",
+			"color": ["#FEC7CE"],
+			"pattern": 1
+		}
+	}`)
+	if err != nil {
+		return nil, err
+	}
+
+	formatSet := fmt.Sprintf(`[{ "type": "cell", "criteria": "=", "format": %d, "value": "0" }]`, format)
+	return &formatSet, nil
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"path/filepath"
+	"pm-report/models"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+const noteColumn = "K" // extra column added by users, it has to be moved together with the row
+
+// saveArchiveTestWrapper saves the wrapper and returns rows of ABC sheet below users header as
+// name, active and note values.
+func saveArchiveTestWrapper(t *testing.T, filePath string, wrapper *models.ProjectConfigWrapper) [][]string {
+	err := NewExcelProjectConfigStorage(filePath).Save(wrapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return getArchiveTestRows(t, filePath)
+}
+
+func getArchiveTestRows(t *testing.T, filePath string) [][]string {
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	context := NewExcelProjectConfigStorage(filePath).createContext()
+	rows, err := f.GetRows("ABC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result [][]string
+	for rowIndex := context.User.HeaderRowIndex + 1; rowIndex <= len(rows); rowIndex++ {
+		var values []string
+		for _, column := range []string{context.User.NameColumn, context.User.ActiveColumn, noteColumn} {
+			value, err := f.GetCellValue("ABC", column+strconv.Itoa(rowIndex))
+			if err != nil {
+				t.Fatal(err)
+			}
+			values = append(values, value)
+		}
+		result = append(result, values)
+	}
+	return result
+}
+
+// setArchiveTestNotes fills note column of each user row with the name and row number of the user,
+// archived section title is skipped.
+func setArchiveTestNotes(t *testing.T, filePath string) {
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	context := NewExcelProjectConfigStorage(filePath).createContext()
+	rows, err := f.GetRows("ABC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nameToCount := map[string]int{}
+	for rowIndex := context.User.HeaderRowIndex + 1; rowIndex <= len(rows); rowIndex++ {
+		name := rows[rowIndex-1][0]
+		active, err := f.GetCellValue("ABC", context.User.ActiveColumn+strconv.Itoa(rowIndex))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if active == archivedValue {
+			continue
+		}
+		nameToCount[name]++
+		err = f.SetCellValue("ABC", noteColumn+strconv.Itoa(rowIndex), name+" "+strconv.Itoa(nameToCount[name]))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = f.Save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func loadArchiveTestUsers(t *testing.T, filePath string) map[string]models.UserConfig {
+	wrapper, err := NewExcelProjectConfigStorage(filePath).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nameToUser := map[string]models.UserConfig{}
+	for _, user := range wrapper.Get("ABC").Users {
+		nameToUser[user.Name] = user
+	}
+	return nameToUser
+}
+
+func assertArchiveTestRows(t *testing.T, got, want [][]string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("rows = %v, want %v", got, want)
+	}
+}
+
+func TestExcelProjectConfigStorageArchive(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.xlsx")
+	wrapper := newTestProjectConfigWrapper()
+	saveArchiveTestWrapper(t, filePath, wrapper)
+	setArchiveTestNotes(t, filePath)
+
+	// user with rate history rows is archived, rows are moved as a group with extra cells
+	users := wrapper.ProjectConfigs[0].Users
+	users[0].Active = false
+	users[0].Archived = true
+	rows := saveArchiveTestWrapper(t, filePath, wrapper)
+	wantRows := [][]string{
+		{"Bob", "Yes", "Bob 1"},
+		{"Archived", "Archived", ""},
+		{"Carol", "No", "Carol 1"},
+		{"Alice", "No", "Alice 1"},
+		{"Alice", "No", "Alice 2"},
+		{"Alice", "No", "Alice 3"},
+	}
+	assertArchiveTestRows(t, rows, wantRows)
+
+	nameToUser := loadArchiveTestUsers(t, filePath)
+	alice := nameToUser["Alice"]
+	if !alice.Archived || alice.Rate != 42.5 || len(alice.RateHistory) != 2 || alice.RateHistory[1].Rate != 60 {
+		t.Fatalf("archived user = %+v, want archived with rate history", alice)
+	}
+	if nameToUser["Bob"].Archived || !nameToUser["Carol"].Archived {
+		t.Fatalf("users = %+v, want Alice and Carol archived", nameToUser)
+	}
+
+	// saving again does not move anything
+	rows = saveArchiveTestWrapper(t, filePath, wrapper)
+	assertArchiveTestRows(t, rows, wantRows)
+
+	// new user is inserted above archived section
+	wrapper.ProjectConfigs[0].Users = append(users, models.UserConfig{AccountId: "d4", Name: "Dave", Rate: 20, Active: true})
+	rows = saveArchiveTestWrapper(t, filePath, wrapper)
+	assertArchiveTestRows(t, rows, [][]string{
+		{"Bob", "Yes", "Bob 1"},
+		{"Dave", "Yes", ""},
+		{"Archived", "Archived", ""},
+		{"Carol", "No", "Carol 1"},
+		{"Alice", "No", "Alice 1"},
+		{"Alice", "No", "Alice 2"},
+		{"Alice", "No", "Alice 3"},
+	})
+
+	// restored user is moved to the end of current users, first user is archived at the same time
+	users = wrapper.ProjectConfigs[0].Users
+	users[0].Active = true
+	users[0].Archived = false
+	users[1].Active = false
+	users[1].Archived = true
+	rows = saveArchiveTestWrapper(t, filePath, wrapper)
+	assertArchiveTestRows(t, rows, [][]string{
+		{"Dave", "Yes", ""},
+		{"Alice", "Yes", "Alice 1"},
+		{"Alice", "Yes", "Alice 2"},
+		{"Alice", "Yes", "Alice 3"},
+		{"Archived", "Archived", ""},
+		{"Carol", "No", "Carol 1"},
+		{"Bob", "No", "Bob 1"},
+	})
+
+	nameToUser = loadArchiveTestUsers(t, filePath)
+	if !nameToUser["Bob"].Archived || nameToUser["Alice"].Archived || len(nameToUser["Alice"].RateHistory) != 2 {
+		t.Fatalf("users = %+v, want Bob archived and Alice restored", nameToUser)
+	}
+}
+
+func TestExcelProjectConfigStorageUserNamedArchived(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.xlsx")
+	wrapper := newTestProjectConfigWrapper()
+	wrapper.ProjectConfigs[0].Users = append(wrapper.ProjectConfigs[0].Users, models.UserConfig{Name: "Archived", Rate: 10, Active: true})
+	saveArchiveTestWrapper(t, filePath, wrapper)
+
+	users := wrapper.ProjectConfigs[0].Users
+	users[1].Active = false
+	users[1].Archived = true
+	rows := saveArchiveTestWrapper(t, filePath, wrapper)
+	assertArchiveTestRows(t, rows, [][]string{
+		{"Alice", "Yes", ""},
+		{"Alice", "Yes", ""},
+		{"Alice", "Yes", ""},
+		{"Archived", "Yes", ""},
+		{"Archived", "Archived", ""},
+		{"Carol", "No", ""},
+		{"Bob", "No", ""},
+	})
+
+	nameToUser := loadArchiveTestUsers(t, filePath)
+	if user, ok := nameToUser["Archived"]; !ok || user.Archived || user.Rate != 10 {
+		t.Fatalf("user named Archived = %+v, want current user", user)
+	}
+	if !nameToUser["Bob"].Archived || nameToUser["Alice"].Archived {
+		t.Fatalf("users = %+v, want Bob archived", nameToUser)
+	}
+}
+
+func TestExcelProjectConfigStorageKeepsUserColumnInPlaceOfActivity(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.xlsx")
+	wrapper := newTestProjectConfigWrapper()
+	wrapper.ProjectConfigs[0].Users[2].Archived = false // archived section did not exist either
+	saveArchiveTestWrapper(t, filePath, wrapper)
+
+	// sheet created before activity columns existed, notes are kept in the first free column
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rowIndex := 7; rowIndex <= 12; rowIndex++ {
+		for _, column := range []string{"H", "I"} {
+			if err = f.SetCellValue("ABC", column+strconv.Itoa(rowIndex), nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for cell, value := range map[string]string{"H7": "Notes", "H8": "keep me"} {
+		if err = f.SetCellValue("ABC", cell, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	saveArchiveTestWrapper(t, filePath, wrapper)
+
+	f, err = excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	want := map[string]string{"H7": "Notes", "H8": "keep me", "I7": "Active", "J7": "Last Seen", "I8": "Yes", "J8": "2026-09-15"}
+	for cell, value := range want {
+		got, err := f.GetCellValue("ABC", cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != value {
+			t.Errorf("%s = %q, want %q", cell, got, value)
+		}
+	}
+
+	nameToUser := loadArchiveTestUsers(t, filePath)
+	if alice := nameToUser["Alice"]; !alice.Active || alice.LastSeen != "2026-09-15" {
+		t.Fatalf("user = %+v, want active and seen on 2026-09-15", alice)
+	}
+
+	issues, err := NewExcelProjectConfigStorage(filePath).Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("issues = %+v, want none", issues)
+	}
+}
+
+func TestExcelProjectConfigStorageSaveKeepsUserChanges(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.xlsx")
+	wrapper := newTestProjectConfigWrapper()
+	saveArchiveTestWrapper(t, filePath, wrapper)
+
+	// user column, custom sheet and styled cell added by users
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cellToValue := map[string]string{"K7": "Notes", "K8": "Alice note", "K13": "Carol note"}
+	for cell, value := range cellToValue {
+		if err = f.SetCellValue("ABC", cell, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.NewSheet("Budget")
+	if err = f.SetCellValue("Budget", "A1", "Q3 budget"); err != nil {
+		t.Fatal(err)
+	}
+	style, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Color: "#ff0000"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = f.SetCellStyle("ABC", "B8", "B8", style); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	wrapper.ProjectConfigs[0].Users[0].Name = "Alice Renamed"
+	wrapper.ProjectConfigs[0].Users = append(wrapper.ProjectConfigs[0].Users, models.UserConfig{AccountId: "d4", Name: "Dave", Rate: 20, Active: true})
+	saveArchiveTestWrapper(t, filePath, wrapper)
+
+	f, err = excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// new user is inserted above archived section, so the archived user and the note move down
+	cellToValue = map[string]string{"K7": "Notes", "A8": "Alice Renamed", "K8": "Alice note",
+		"A12": "Dave", "A13": "Archived", "A14": "Carol", "K14": "Carol note"}
+	for cell, value := range cellToValue {
+		got, err := f.GetCellValue("ABC", cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != value {
+			t.Errorf("ABC!%s = %q, want %q", cell, got, value)
+		}
+	}
+
+	budget, err := f.GetCellValue("Budget", "A1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if budget != "Q3 budget" {
+		t.Errorf("Budget!A1 = %q, want custom sheet kept", budget)
+	}
+
+	gotStyle, err := f.GetCellStyle("ABC", "B8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotStyle != style {
+		t.Errorf("ABC!B8 style = %d, want %d", gotStyle, style)
+	}
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"os"
+	"pm-report/models"
+	"strconv"
+)
+
+// Validate checks titles of project info, users table and rate card, rates are checked to be numbers
+// and dates to be parseable. Sheets added by users (without `Key` title) are skipped.
+func (s *ExcelProjectConfigStorage) Validate() ([]models.ProjectConfigIssue, error) {
+	_, err := os.Stat(s.filePath)
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	f, err := excelize.OpenFile(s.filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	context := s.createContext()
+	var issues []models.ProjectConfigIssue
+
+	for _, sheet := range f.GetSheetList() {
+		var sheetIssues []models.ProjectConfigIssue
+
+		switch sheet {
+		case peopleSheet:
+			sheetIssues, err = s.validateUsers(f, sheet, s.createPeopleContext())
+		case rateCardSheet:
+			sheetIssues, err = s.validateRateCards(f)
+		default:
+			var keyTitle string
+			keyTitle, err = f.GetCellValue(sheet, context.Project.KeyTitleCell)
+			if err != nil {
+				return nil, err
+			}
+			if keyTitle != keyTitleValue {
+				continue // sheet added by user
+			}
+
+			sheetIssues, err = s.validateProjectInfo(f, sheet, context)
+			if err != nil {
+				return nil, err
+			}
+			issues = append(issues, sheetIssues...)
+
+			sheetIssues, err = s.validateUsers(f, sheet, context)
+		}
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, sheetIssues...)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return issues, nil
+}
+
+func (s *ExcelProjectConfigStorage) validateProjectInfo(f *excelize.File, sheet string, context *models.ProjectConfigContext) ([]models.ProjectConfigIssue, error) {
+	var issues []models.ProjectConfigIssue
+
+	header, err := f.GetCellValue(sheet, context.Project.HeaderCell)
+	if err != nil {
+		return nil, err
+	}
+	if header != activeHeaderValue && header != inactiveHeaderValue {
+		issues = append(issues, s.createHeaderIssue(sheet, context.Project.HeaderCell, header, activeHeaderValue))
+	}
+
+	key, err := f.GetCellValue(sheet, context.Project.KeyValueCell)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) > 0 && key != sheet {
+		issues = append(issues, s.createHeaderIssue(sheet, context.Project.KeyValueCell, key, sheet))
+	}
+
+	cellToTitle := [][]string{
+		{context.Project.DisplayNameTitleCell, "Display Name"},
+		{context.Project.OwnerTitleCell, "Owner"},
+		{context.Project.ManagerTitleCell, "Manager"},
+	}
+	for _, pair := range cellToTitle {
+		title, err := f.GetCellValue(sheet, pair[0])
+		if err != nil {
+			return nil, err
+		}
+		if title != pair[1] {
+			issues = append(issues, s.createHeaderIssue(sheet, pair[0], title, pair[1]))
+		}
+	}
+
+	return issues, nil
+}
+
+// validateUsers checks titles of users table (empty titles are fine, they are added on sync),
+// rate, effective date and last seen date of each user row.
+func (s *ExcelProjectConfigStorage) validateUsers(f *excelize.File, sheet string, context *models.ProjectConfigContext) ([]models.ProjectConfigIssue, error) {
+	var issues []models.ProjectConfigIssue
+	headerRowIndex := strconv.Itoa(context.User.HeaderRowIndex)
+
+	context, err := s.resolveActivityColumns(f, sheet, context)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pair := range s.getUserTitles(context) {
+		title, err := f.GetCellValue(sheet, pair[0]+headerRowIndex)
+		if err != nil {
+			return nil, err
+		}
+		if len(title) > 0 && title != pair[1] {
+			issues = append(issues, s.createHeaderIssue(sheet, pair[0]+headerRowIndex, title, pair[1]))
+		}
+	}
+
+	rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, err
+	}
+	archivedRowIndex := s.findArchivedRowIndex(rows, context)
+
+	for rowIndex := context.User.HeaderRowIndex + 1; rowIndex <= len(rows); rowIndex++ {
+		rowCols := rows[rowIndex-1]
+		name := s.getColumnValue(rowCols, context.User.NameColumn)
+		if len(name) == 0 || rowIndex == archivedRowIndex {
+			continue
+		}
+		row := strconv.Itoa(rowIndex)
+
+		if rate := s.getColumnValue(rowCols, context.User.RateColumn); len(rate) > 0 {
+			if _, err = strconv.ParseFloat(rate, 64); err != nil {
+				issues = append(issues, models.ProjectConfigIssue{Sheet: sheet, Subject: name, Issue: nonNumericCellIssue,
+					Details: fmt.Sprintf("%s: %q", context.User.RateColumn+row, rate)})
+			}
+		}
+
+		for _, column := range []string{context.User.EffectiveFromColumn, context.User.LastSeenColumn} {
+			if date := s.getColumnValue(rowCols, column); len(date) > 0 {
+				if _, err = s.parseDate(date); err != nil {
+					issues = append(issues, models.ProjectConfigIssue{Sheet: sheet, Subject: name, Issue: unparseableDateIssue,
+						Details: fmt.Sprintf("%s: %q", column+row, date)})
+				}
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+func (s *ExcelProjectConfigStorage) validateRateCards(f *excelize.File) ([]models.ProjectConfigIssue, error) {
+	var issues []models.ProjectConfigIssue
+
+	rows, err := f.GetRows(rateCardSheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, title := range rateCardTitles {
+		col, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return nil, err
+		}
+		value := ""
+		if len(rows) > 0 {
+			value = s.getColumnValue(rows[0], col)
+		}
+		if value != title {
+			issues = append(issues, s.createHeaderIssue(rateCardSheet, col+"1", value, title))
+		}
+	}
+
+	for rowIndex := 2; rowIndex <= len(rows); rowIndex++ {
+		position := s.getColumnValue(rows[rowIndex-1], "A")
+		if len(position) == 0 {
+			continue
+		}
+
+		if rate := s.getColumnValue(rows[rowIndex-1], "B"); len(rate) > 0 {
+			if _, err = strconv.ParseFloat(rate, 64); err != nil {
+				issues = append(issues, models.ProjectConfigIssue{Sheet: rateCardSheet, Subject: position, Issue: nonNumericCellIssue,
+					Details: fmt.Sprintf("B%d: %q", rowIndex, rate)})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+func (s *ExcelProjectConfigStorage) createHeaderIssue(sheet, cell, value, expectedValue string) models.ProjectConfigIssue {
+	return models.ProjectConfigIssue{
+		Sheet:   sheet,
+		Subject: cell,
+		Issue:   unparseableHeaderIssue,
+		Details: fmt.Sprintf("%q, expected %q", value, expectedValue),
+	}
+}
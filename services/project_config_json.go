@@ -0,0 +1,61 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"pm-report/models"
+)
+
+// JsonProjectConfigStorage keeps project configs in a json file.
+type JsonProjectConfigStorage struct {
+	filePath string
+}
+
+func NewJsonProjectConfigStorage(filePath string) *JsonProjectConfigStorage {
+	return &JsonProjectConfigStorage{filePath: filePath}
+}
+
+func (s *JsonProjectConfigStorage) Load() (*models.ProjectConfigWrapper, error) {
+	data, err := ioutil.ReadFile(s.filePath)
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return &models.ProjectConfigWrapper{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	projectConfigWrapper := &models.ProjectConfigWrapper{}
+	err = json.Unmarshal(data, projectConfigWrapper)
+	if err != nil {
+		return nil, err
+	}
+
+	// hand-edited rate history can be out of order
+	err = projectConfigWrapper.SortRateHistories()
+	if err != nil {
+		return nil, err
+	}
+
+	return projectConfigWrapper, nil
+}
+
+func (s *JsonProjectConfigStorage) Save(projectConfigWrapper *models.ProjectConfigWrapper) error {
+	data, err := json.MarshalIndent(projectConfigWrapper, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(s.filePath, data, 0644)
+}
+
+// LoadForValidation is the same as Load, json file cannot be read partially.
+func (s *JsonProjectConfigStorage) LoadForValidation() (*models.ProjectConfigWrapper, error) {
+	return s.Load()
+}
+
+// Validate does not check anything, json file has no structure besides the one checked on loading.
+func (s *JsonProjectConfigStorage) Validate() ([]models.ProjectConfigIssue, error) {
+	return nil, nil
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"pm-report/models"
+	"strings"
+)
+
+// ProjectConfigStorage reads and writes project configs in a file of specific format.
+type ProjectConfigStorage interface {
+	// Load reads project configs, empty configs are returned if the file does not exist.
+	Load() (*models.ProjectConfigWrapper, error)
+	// LoadForValidation reads project configs skipping values which cannot be parsed (they are reported by Validate),
+	// so that the rest of the file can be validated.
+	LoadForValidation() (*models.ProjectConfigWrapper, error)
+	// Save writes project configs, the file is created if it does not exist.
+	Save(projectConfigWrapper *models.ProjectConfigWrapper) error
+	// Validate checks structure of the file which cannot be checked on loaded project configs
+	// (e.g. titles and non-numeric cells), no issues are returned if the file does not exist.
+	Validate() ([]models.ProjectConfigIssue, error)
+}
+
+// NewProjectConfigStorage chooses storage by file extension: xlsx, yaml (yml), json or csv.
+func NewProjectConfigStorage(filePath string) (ProjectConfigStorage, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".xlsx":
+		return NewExcelProjectConfigStorage(filePath), nil
+	case ".yaml", ".yml":
+		return NewYamlProjectConfigStorage(filePath), nil
+	case ".json":
+		return NewJsonProjectConfigStorage(filePath), nil
+	case ".csv":
+		return NewCsvProjectConfigStorage(filePath), nil
+	}
+	return nil, errors.New("error: format of project config file is not supported: " + filePath)
+}
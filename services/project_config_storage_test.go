@@ -0,0 +1,121 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"pm-report/models"
+	"reflect"
+	"testing"
+)
+
+func TestProjectConfigStorageRoundTrip(t *testing.T) {
+	for _, fileName := range []string{"config.xlsx", "config.yaml", "config.json", "config.csv"} {
+		t.Run(fileName, func(t *testing.T) {
+			storage, err := NewProjectConfigStorage(filepath.Join(t.TempDir(), fileName))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := newTestProjectConfigWrapper()
+			err = storage.Save(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := storage.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("loaded config = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestProjectConfigConvertRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	filePaths := []string{"source.xlsx", "config.yaml", "config.json", "config.csv", "target.xlsx"}
+	for i := range filePaths {
+		filePaths[i] = filepath.Join(dir, filePaths[i])
+	}
+
+	want := newTestProjectConfigWrapper()
+	err := NewExcelProjectConfigStorage(filePaths[0]).Save(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i < len(filePaths); i++ {
+		err = NewProjectConfigConvertService().Convert(filePaths[i-1], filePaths[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := NewExcelProjectConfigStorage(filePaths[len(filePaths)-1]).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("converted config = %+v, want %+v", got, want)
+	}
+}
+
+func TestProjectConfigStorageSortsRateHistory(t *testing.T) {
+	tests := []struct {
+		fileName string
+		data     string
+	}{
+		{fileName: "config.yaml", data: `projects:
+  - key: ABC
+    users:
+      - name: Alice
+        rate: 40
+        rate_history:
+          - effective_from: 2026-09-01
+            rate: 60
+          - effective_from: "2026-03-01"
+            rate: 50
+`},
+		{fileName: "config.json", data: `{"projects": [{"key": "ABC", "users": [{"name": "Alice", "rate": 40, "rate_history": [
+  {"effective_from": "2026-09-01", "rate": 60}, {"effective_from": "2026-03-01", "rate": 50}]}]}]}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fileName, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), test.fileName)
+			err := os.WriteFile(filePath, []byte(test.data), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wrapper, err := NewProjectConfigService(filePath, false, 0, false, nil).Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rateHistory := wrapper.Get("ABC").Users[0].RateHistory
+			want := models.RateHistory{{EffectiveFrom: "2026-03-01", Rate: 50}, {EffectiveFrom: "2026-09-01", Rate: 60}}
+			if !reflect.DeepEqual(rateHistory, want) {
+				t.Fatalf("rate history = %+v, want %+v", rateHistory, want)
+			}
+			if rate := rateHistory.Get(40, "2026-06-15"); rate != 50 {
+				t.Fatalf("rate = %v, want 50", rate)
+			}
+		})
+	}
+}
+
+func TestProjectConfigStorageRejectsInvalidEffectiveDate(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(filePath, []byte("projects:\n  - key: ABC\n    users:\n      - name: Alice\n        rate_history:\n          - effective_from: 01.09.2026\n            rate: 60\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewYamlProjectConfigStorage(filePath).Load()
+	if err == nil || err.Error() != "error: effective date of Alice in ABC project config is not recognized: 01.09.2026" {
+		t.Fatalf("err = %v, want unrecognized effective date", err)
+	}
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"net/http"
+	"path/filepath"
+	"pm-report/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProjectConfigServiceIsStale(t *testing.T) {
+	date := time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		archiveAfterMonths int
+		lastSeen           string
+		want               bool
+	}{
+		{name: "archiving is disabled", archiveAfterMonths: 0, lastSeen: "2025-01-01", want: false},
+		{name: "never seen", archiveAfterMonths: 3, lastSeen: "", want: false},
+		{name: "seen within period", archiveAfterMonths: 3, lastSeen: "2026-07-01", want: false},
+		{name: "seen on the boundary", archiveAfterMonths: 3, lastSeen: "2026-06-30", want: false},
+		{name: "seen before the boundary", archiveAfterMonths: 3, lastSeen: "2026-06-29", want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			projectConfigService := NewProjectConfigService("", false, test.archiveAfterMonths, false, nil)
+			if got := projectConfigService.isStale(models.UserConfig{LastSeen: test.lastSeen}, date); got != test.want {
+				t.Fatalf("isStale(%s) = %v, want %v", test.lastSeen, got, test.want)
+			}
+		})
+	}
+}
+
+func TestProjectConfigServiceUpdateProjectConfigsArchivesStaleUsers(t *testing.T) {
+	wrapper := &models.ProjectConfigWrapper{ProjectConfigs: []models.ProjectConfig{{
+		Key: "ABC",
+		Users: []models.UserConfig{
+			{AccountId: "a1", Name: "Alice", Active: true, LastSeen: "2026-01-15"},
+			{AccountId: "b2", Name: "Bob", Active: true, LastSeen: "2026-08-20"},
+			{AccountId: "c3", Name: "Carol", Archived: true, LastSeen: "2026-01-15"},
+		},
+	}}}
+	report := &models.Report{
+		DateTo: time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC),
+		Projects: []models.Project{{Key: "ABC", Users: []models.User{
+			{AccountId: "c3", Name: "Carol", Issues: []models.Issue{{Key: "ABC-1", Efforts: []models.Effort{{Date: "2026-09-10"}}}}},
+		}}},
+	}
+
+	updated := NewProjectConfigService("", false, 3, false, nil).updateProjectConfigs(wrapper, report)
+
+	want := map[string]models.UserConfig{
+		"Alice": {AccountId: "a1", Name: "Alice", Active: false, Archived: true, LastSeen: "2026-01-15"},
+		"Bob":   {AccountId: "b2", Name: "Bob", Active: false, Archived: false, LastSeen: "2026-08-20"},
+		"Carol": {AccountId: "c3", Name: "Carol", Active: true, Archived: false, LastSeen: "2026-09-10"},
+	}
+	users := updated.Get("ABC").Users
+	if len(users) != len(want) {
+		t.Fatalf("users = %+v, want %d users", users, len(want))
+	}
+	for _, user := range users {
+		if !reflect.DeepEqual(user, want[user.Name]) {
+			t.Errorf("user = %+v, want %+v", user, want[user.Name])
+		}
+	}
+}
+
+func TestProjectConfigServiceUpdateProjectConfigsStartsArchivingOfNeverSeenUsers(t *testing.T) {
+	wrapper := &models.ProjectConfigWrapper{ProjectConfigs: []models.ProjectConfig{{
+		Key:   "ABC",
+		Users: []models.UserConfig{{AccountId: "a1", Name: "Alice", Active: true}},
+	}}}
+	projectConfigService := NewProjectConfigService("", false, 3, false, nil)
+
+	// first run starts archiving period of the user without last seen date
+	report := &models.Report{
+		DateFrom: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		DateTo:   time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC),
+		Projects: []models.Project{{Key: "ABC"}},
+	}
+	wrapper = projectConfigService.updateProjectConfigs(wrapper, report)
+	want := models.UserConfig{AccountId: "a1", Name: "Alice", LastSeen: "2026-01-01"}
+	if user := wrapper.Get("ABC").Users[0]; !reflect.DeepEqual(user, want) {
+		t.Fatalf("user = %+v, want %+v", user, want)
+	}
+
+	// user is archived when the period is over
+	report.DateFrom = time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	report.DateTo = time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC)
+	wrapper = projectConfigService.updateProjectConfigs(wrapper, report)
+	want.Archived = true
+	if user := wrapper.Get("ABC").Users[0]; !reflect.DeepEqual(user, want) {
+		t.Fatalf("user = %+v, want %+v", user, want)
+	}
+}
+
+func TestProjectConfigServiceUpdatePeopleKeepsEmailOfUnresolvedUser(t *testing.T) {
+	server := newTestJiraServer(t, map[string]testJiraHandler{
+		"/rest/api/3/user": func(request *http.Request) (interface{}, int) {
+			if request.URL.Query().Get("accountId") != "a1" {
+				return nil, http.StatusNotFound // deactivated or deleted account
+			}
+			return models.JiraUser{AccountId: "a1", EmailAddress: "alice@example.com"}, 0
+		},
+	})
+
+	jiraService := NewJiraService(models.JiraAppConfig{Url: server.URL, ResolveEmails: true})
+	report := &models.Report{Projects: []models.Project{{Key: "ABC", Users: []models.User{
+		{AccountId: "a1", Name: "Alice"},
+		{AccountId: "b2", Name: "Bob"},
+		{AccountId: "c3", Name: "Carol"},
+	}}}}
+	people := []models.UserConfig{{AccountId: "c3", Name: "Carol", Email: "carol@example.com"}}
+
+	got := NewProjectConfigService("", false, 0, false, jiraService).updatePeople(people, report)
+	want := []models.UserConfig{
+		{AccountId: "c3", Name: "Carol", Email: "carol@example.com"},
+		{AccountId: "a1", Name: "Alice", Email: "alice@example.com"},
+		{AccountId: "b2", Name: "Bob"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("people = %+v, want %+v", got, want)
+	}
+}
+
+func TestProjectConfigServiceUpdateProjectConfigsStoresPrefilledInfoOfNewProjects(t *testing.T) {
+	wrapper := &models.ProjectConfigWrapper{ProjectConfigs: []models.ProjectConfig{{Key: "ABC", Owner: "ClientCo"}}}
+	report := &models.Report{Projects: []models.Project{
+		{Key: "ABC", DisplayName: "Alpha", Owner: "ClientCo", Manager: "Max Lead"}, // prefilled from Jira
+		{Key: "XYZ", DisplayName: "Xylophone", Manager: "Max Lead"},
+	}}
+
+	updated := NewProjectConfigService("", false, 0, false, nil).updateProjectConfigs(wrapper, report)
+
+	want := []models.ProjectConfig{
+		{Key: "ABC", Owner: "ClientCo"},
+		{Key: "XYZ", DisplayName: "Xylophone", Manager: "Max Lead"},
+	}
+	if !reflect.DeepEqual(updated.ProjectConfigs, want) {
+		t.Fatalf("project configs = %+v, want %+v", updated.ProjectConfigs, want)
+	}
+}
+
+func TestProjectConfigServiceUpdateProjectConfigsKeepsProjectsNotInRun(t *testing.T) {
+	wrapper := newTestProjectConfigWrapper()
+	wrapper.ProjectConfigs[1].Inactive = false
+	report := &models.Report{Projects: []models.Project{{Key: "ABC"}}}
+
+	tests := []struct {
+		name         string
+		markInactive bool
+	}{
+		{name: "kept as is", markInactive: false},
+		{name: "marked as inactive", markInactive: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			updated := NewProjectConfigService("", test.markInactive, 0, false, nil).updateProjectConfigs(wrapper, report)
+
+			want := wrapper.ProjectConfigs[1]
+			want.Inactive = test.markInactive
+			if got := updated.Get("XYZ"); got == nil || !reflect.DeepEqual(*got, want) {
+				t.Fatalf("project config = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestProjectConfigServiceUpdateProjectConfigsReactivatesReturningProject(t *testing.T) {
+	wrapper := newTestProjectConfigWrapper()
+	report := &models.Report{Projects: []models.Project{{Key: "XYZ", Users: []models.User{
+		{AccountId: "b2", Name: "Bob", Issues: []models.Issue{{Key: "XYZ-1", Efforts: []models.Effort{{Date: "2026-09-10"}}}}},
+	}}}}
+
+	updated := NewProjectConfigService("", true, 0, false, nil).updateProjectConfigs(wrapper, report)
+
+	xyz := updated.Get("XYZ")
+	if xyz == nil || xyz.Inactive || len(xyz.Users) != 1 || xyz.Users[0].Rate != 45 {
+		t.Fatalf("project config = %+v, want active with rates kept", xyz)
+	}
+	if abc := updated.Get("ABC"); abc == nil || !abc.Inactive || len(abc.Users) != 3 {
+		t.Fatalf("project config = %+v, want inactive with users kept", abc)
+	}
+}
+
+func TestProjectConfigServiceSaveKeepsSheetsOfProjectsNotInRun(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.xlsx")
+	wrapper := newTestProjectConfigWrapper()
+	wrapper.ProjectConfigs[1].Inactive = false
+	err := NewExcelProjectConfigStorage(filePath).Save(wrapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := &models.Report{Projects: []models.Project{{Key: "ABC"}}}
+	projectConfigService := NewProjectConfigService(filePath, true, 0, false, nil)
+	err = projectConfigService.Save(wrapper, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := projectConfigService.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := wrapper.ProjectConfigs[1]
+	want.Inactive = true
+	if got := saved.Get("XYZ"); got == nil || !reflect.DeepEqual(*got, want) {
+		t.Fatalf("project config = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"pm-report/models"
+	"pm-report/utils"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	missingRateIssue       = "MISSING RATE"
+	zeroRateIssue          = "ZERO RATE"
+	duplicateNameIssue     = "DUPLICATE NAME"
+	nonNumericCellIssue    = "NON-NUMERIC CELL"
+	unparseableDateIssue   = "UNPARSEABLE DATE"
+	unparseableHeaderIssue = "UNPARSEABLE HEADER"
+	unknownProjectIssue    = "UNKNOWN PROJECT"
+)
+
+// Validate checks project config file: structure of the file (titles, non-numeric and unparseable cells),
+// users without rate (people registry and rate card are taken into account, archived users are skipped),
+// duplicate user names and projects which are not configured in tokens (skipped if projects are discovered
+// and for inactive project configs). Error is returned if the file cannot be read at all.
+func (s *ProjectConfigService) Validate(tempoAppConfig models.TempoAppConfig) ([]models.ProjectConfigIssue, error) {
+	storage, err := NewProjectConfigStorage(s.filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := storage.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	projectConfigWrapper, err := storage.LoadForValidation()
+	if err != nil {
+		return nil, err
+	}
+
+	knownProjectKeys := map[string]bool{}
+	for _, token := range tempoAppConfig.Tokens {
+		for _, projectKey := range utils.ToList(token.Projects) {
+			knownProjectKeys[projectKey] = true
+		}
+	}
+
+	for _, projectConfig := range projectConfigWrapper.ProjectConfigs {
+		// inactive project configs are kept on purpose (e.g. reduced project list or removed token)
+		if tempoAppConfig.Discovery.Mode != models.IncludeDiscoveryMode && !projectConfig.Inactive && !knownProjectKeys[projectConfig.Key] {
+			issues = append(issues, models.ProjectConfigIssue{Sheet: projectConfig.Key, Subject: "project", Issue: unknownProjectIssue,
+				Details: "project is not listed in any token"})
+		}
+
+		issues = append(issues, s.validateNames(projectConfig.Key, projectConfig.Users)...)
+		issues = append(issues, s.validateRates(&projectConfig, projectConfigWrapper)...)
+	}
+
+	issues = append(issues, s.validateNames(peopleSheet, projectConfigWrapper.People)...)
+
+	for _, rateCard := range projectConfigWrapper.RateCards {
+		if rateCard.Rate == 0 {
+			issues = append(issues, models.ProjectConfigIssue{Sheet: rateCardSheet, Subject: rateCard.Position, Issue: zeroRateIssue})
+		}
+	}
+
+	return issues, nil
+}
+
+// validateNames finds different users (e.g. with different account ids) having the same name.
+func (s *ProjectConfigService) validateNames(sheet string, users []models.UserConfig) []models.ProjectConfigIssue {
+	var issues []models.ProjectConfigIssue
+	nameToAccountIds := map[string][]string{}
+	var names []string
+
+	for _, user := range users {
+		if _, ok := nameToAccountIds[user.Name]; !ok {
+			names = append(names, user.Name)
+		}
+		nameToAccountIds[user.Name] = append(nameToAccountIds[user.Name], user.AccountId)
+	}
+
+	for _, name := range names {
+		if accountIds := nameToAccountIds[name]; len(accountIds) > 1 {
+			issues = append(issues, models.ProjectConfigIssue{Sheet: sheet, Subject: name, Issue: duplicateNameIssue,
+				Details: "Account Ids: " + strings.Join(accountIds, ", ")})
+		}
+	}
+
+	return issues
+}
+
+// validateRates finds users without rate the same way as it is resolved for report:
+// project overrides first, then people registry defaults, then rate card by position.
+func (s *ProjectConfigService) validateRates(projectConfig *models.ProjectConfig, projectConfigWrapper *models.ProjectConfigWrapper) []models.ProjectConfigIssue {
+	var issues []models.ProjectConfigIssue
+
+	for _, user := range projectConfig.Users {
+		if user.Archived {
+			continue
+		}
+
+		effectiveUser := user.WithDefaults(projectConfigWrapper.GetPerson(user.AccountId, user.Name))
+		for _, rateConfig := range effectiveUser.RateHistory {
+			if rateConfig.Rate == 0 {
+				issues = append(issues, models.ProjectConfigIssue{Sheet: projectConfig.Key, Subject: user.Name, Issue: zeroRateIssue,
+					Details: "Effective From: " + rateConfig.EffectiveFrom})
+			}
+		}
+		if effectiveUser.Rate != 0 || len(effectiveUser.RateHistory) > 0 {
+			continue
+		}
+
+		if len(effectiveUser.Position) > 0 {
+			rateCard := projectConfigWrapper.GetRateCard(effectiveUser.Position, projectConfig.Key, projectConfig.Owner)
+			if rateCard != nil && rateCard.Rate != 0 {
+				continue
+			}
+		}
+
+		issues = append(issues, models.ProjectConfigIssue{Sheet: projectConfig.Key, Subject: user.Name, Issue: missingRateIssue,
+			Details: "no rate in project sheet, people registry and rate card"})
+	}
+
+	return issues
+}
+
+func (s *ProjectConfigService) PrintIssues(w io.Writer, issues []models.ProjectConfigIssue) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, err := fmt.Fprintln(tw, "SHEET\tSUBJECT\tISSUE\tDETAILS")
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		_, err = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", issue.Sheet, issue.Subject, issue.Issue, issue.Details)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"os"
+	"path/filepath"
+	"pm-report/models"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+var validationTestTempoAppConfig = models.TempoAppConfig{Tokens: []models.TokenTempoAppConfig{{Token: "token", Projects: "ABC"}}}
+
+// getIssueKinds lists issues as "sheet subject issue" for comparison.
+func getIssueKinds(issues []models.ProjectConfigIssue) []string {
+	var kinds []string
+	for _, issue := range issues {
+		kinds = append(kinds, issue.Sheet+" "+issue.Subject+" "+issue.Issue)
+	}
+	return kinds
+}
+
+func TestProjectConfigServiceValidateContinuesAfterInvalidCell(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.xlsx")
+	err := NewExcelProjectConfigStorage(filePath).Save(&models.ProjectConfigWrapper{ProjectConfigs: []models.ProjectConfig{{
+		Key: "ABC",
+		Users: []models.UserConfig{
+			{AccountId: "a1", Name: "Alice", Rate: 30},
+			{AccountId: "b2", Name: "Bob"},
+		},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rate of the first user is not a number
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	context := NewExcelProjectConfigStorage(filePath).createContext()
+	err = f.SetCellValue("ABC", context.User.RateColumn+strconv.Itoa(context.User.HeaderRowIndex+1), "thirty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	projectConfigService := NewProjectConfigService(filePath, false, 0, false, nil)
+	issues, err := projectConfigService.Validate(validationTestTempoAppConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"ABC Alice NON-NUMERIC CELL", "ABC Bob MISSING RATE"}
+	if got := getIssueKinds(issues); !reflect.DeepEqual(got, want) {
+		t.Fatalf("issues = %v, want %v", got, want)
+	}
+	// report is not created with invalid cell in any mode
+	_, err = projectConfigService.Get()
+	if err == nil {
+		t.Fatal("project config with invalid cell should not be loaded")
+	}
+}
+
+func TestProjectConfigServiceValidateUnknownProjects(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+	err := NewYamlProjectConfigStorage(filePath).Save(&models.ProjectConfigWrapper{ProjectConfigs: []models.ProjectConfig{
+		{Key: "ABC", Users: []models.UserConfig{{Name: "Alice", Rate: 30}}},
+		{Key: "OLD", Users: []models.UserConfig{{Name: "Alice", Rate: 30}}, Inactive: true},
+		{Key: "XYZ", Users: []models.UserConfig{{Name: "Alice", Rate: 30}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	projectConfigService := NewProjectConfigService(filePath, false, 0, false, nil)
+	issues, err := projectConfigService.Validate(validationTestTempoAppConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"XYZ project UNKNOWN PROJECT"}
+	if got := getIssueKinds(issues); !reflect.DeepEqual(got, want) {
+		t.Fatalf("issues = %v, want %v", got, want)
+	}
+}
+
+func TestProjectConfigServiceValidateUnreadableFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(filePath, []byte("projects: [\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewProjectConfigService(filePath, false, 0, false, nil).Validate(validationTestTempoAppConfig)
+	if err == nil {
+		t.Fatal("unreadable project config should stop validation with error")
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"pm-report/models"
+)
+
+// YamlProjectConfigStorage keeps project configs in a yaml file (e.g. reviewed in git).
+type YamlProjectConfigStorage struct {
+	filePath string
+}
+
+func NewYamlProjectConfigStorage(filePath string) *YamlProjectConfigStorage {
+	return &YamlProjectConfigStorage{filePath: filePath}
+}
+
+func (s *YamlProjectConfigStorage) Load() (*models.ProjectConfigWrapper, error) {
+	data, err := ioutil.ReadFile(s.filePath)
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return &models.ProjectConfigWrapper{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	projectConfigWrapper := &models.ProjectConfigWrapper{}
+	err = yaml.Unmarshal(data, projectConfigWrapper)
+	if err != nil {
+		return nil, err
+	}
+
+	// hand-edited rate history can be out of order
+	err = projectConfigWrapper.SortRateHistories()
+	if err != nil {
+		return nil, err
+	}
+
+	return projectConfigWrapper, nil
+}
+
+func (s *YamlProjectConfigStorage) Save(projectConfigWrapper *models.ProjectConfigWrapper) error {
+	var buffer bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+
+	err := encoder.Encode(projectConfigWrapper)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(s.filePath, buffer.Bytes(), 0644)
+}
+
+// LoadForValidation is the same as Load, yaml file cannot be read partially.
+func (s *YamlProjectConfigStorage) LoadForValidation() (*models.ProjectConfigWrapper, error) {
+	return s.Load()
+}
+
+// Validate does not check anything, yaml file has no structure besides the one checked on loading.
+func (s *YamlProjectConfigStorage) Validate() ([]models.ProjectConfigIssue, error) {
+	return nil, nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"log"
+	"pm-report/models"
+	"pm-report/utils"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiter safe for concurrent use.
+// The bucket lives in memory of the process, so it does not coordinate several runs sharing a token.
+type RateLimiter struct {
+	mutex             sync.Mutex
+	requestsPerSecond float64
+	burst             float64
+	tokens            float64
+	lastRefill        time.Time
+	blockedUntil      time.Time // set by backoff after throttling response
+	waited            time.Duration
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		requestsPerSecond: requestsPerSecond,
+		burst:             float64(burst),
+		tokens:            float64(burst),
+		lastRefill:        time.Now(),
+		now:               time.Now,
+		sleep:             time.Sleep,
+	}
+}
+
+// Wait blocks until a request is allowed.
+func (s *RateLimiter) Wait() {
+	s.mutex.Lock()
+
+	now := s.now()
+
+	// backoff after throttling response
+	delay := s.blockedUntil.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+
+	if s.requestsPerSecond > 0 {
+		if now.After(s.lastRefill) {
+			s.tokens += now.Sub(s.lastRefill).Seconds() * s.requestsPerSecond
+			if s.tokens > s.burst {
+				s.tokens = s.burst
+			}
+			s.lastRefill = now
+		}
+
+		// reserve a token, negative balance is a debt paid by waiting
+		s.tokens--
+		if s.tokens < 0 {
+			delay += time.Duration(-s.tokens / s.requestsPerSecond * float64(time.Second))
+		}
+	}
+	s.waited += delay
+
+	s.mutex.Unlock()
+
+	if delay > 0 {
+		s.sleep(delay)
+	}
+}
+
+// Backoff blocks further requests for the delay (e.g. after 429 Too Many Requests response),
+// then the bucket is refilled from scratch with a single token for the retry.
+func (s *RateLimiter) Backoff(delay time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if blockedUntil := s.now().Add(delay); blockedUntil.After(s.blockedUntil) {
+		s.blockedUntil = blockedUntil
+	}
+	s.tokens = 1
+	s.lastRefill = s.blockedUntil
+}
+
+func (s *RateLimiter) Waited() time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.waited
+}
+
+// RateLimiterRegistry keeps a rate limiter per token.
+type RateLimiterRegistry struct {
+	mutex          sync.Mutex
+	config         models.RateLimitTempoAppConfig
+	tokenToLimiter map[string]*RateLimiter
+}
+
+func NewRateLimiterRegistry(config models.RateLimitTempoAppConfig) *RateLimiterRegistry {
+	return &RateLimiterRegistry{
+		config:         config,
+		tokenToLimiter: map[string]*RateLimiter{},
+	}
+}
+
+// Wait blocks until a request with the token is allowed, only backoff is applied if rate limiting is disabled.
+func (s *RateLimiterRegistry) Wait(token string) {
+	s.get(token).Wait()
+}
+
+// Backoff blocks further requests with the token for the delay.
+func (s *RateLimiterRegistry) Backoff(token string, delay time.Duration) {
+	s.get(token).Backoff(delay)
+}
+
+func (s *RateLimiterRegistry) get(token string) *RateLimiter {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	limiter, ok := s.tokenToLimiter[token]
+	if !ok {
+		limiter = NewRateLimiter(s.config.RequestsPerSecond, s.config.Burst)
+		s.tokenToLimiter[token] = limiter
+	}
+	return limiter
+}
+
+func (s *RateLimiterRegistry) LogStats() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tokens := make([]string, 0, len(s.tokenToLimiter))
+	for token := range s.tokenToLimiter {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+
+	for _, token := range tokens {
+		log.Println("Time spent waiting on rate limiter for token", utils.Mask(token)+":",
+			s.tokenToLimiter[token].Waited().Round(time.Millisecond))
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func (s *fakeClock) Now() time.Time {
+	return s.now
+}
+
+// Sleep advances the clock instead of blocking.
+func (s *fakeClock) Sleep(delay time.Duration) {
+	s.slept = append(s.slept, delay)
+	s.now = s.now.Add(delay)
+}
+
+func newTestRateLimiter(requestsPerSecond float64, burst int) (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)}
+	limiter := NewRateLimiter(requestsPerSecond, burst)
+	limiter.now = clock.Now
+	limiter.sleep = clock.Sleep
+	limiter.lastRefill = clock.now
+	return limiter, clock
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter, clock := newTestRateLimiter(2, 3)
+
+	for i := 0; i < 3; i++ {
+		limiter.Wait()
+	}
+	if len(clock.slept) != 0 {
+		t.Fatalf("requests within burst should not wait, slept %v", clock.slept)
+	}
+
+	limiter.Wait()
+	if len(clock.slept) != 1 || clock.slept[0] != 500*time.Millisecond {
+		t.Fatalf("request over burst should wait 500ms, slept %v", clock.slept)
+	}
+	if limiter.Waited() != 500*time.Millisecond {
+		t.Fatalf("waited = %v, want 500ms", limiter.Waited())
+	}
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	limiter, clock := newTestRateLimiter(2, 2)
+
+	limiter.Wait()
+	limiter.Wait()
+
+	// one second refills two tokens
+	clock.now = clock.now.Add(time.Second)
+	limiter.Wait()
+	limiter.Wait()
+	if len(clock.slept) != 0 {
+		t.Fatalf("refilled requests should not wait, slept %v", clock.slept)
+	}
+
+	// refill is capped by burst
+	clock.now = clock.now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		limiter.Wait()
+	}
+	if len(clock.slept) != 1 || clock.slept[0] != 500*time.Millisecond {
+		t.Fatalf("request over capped burst should wait 500ms, slept %v", clock.slept)
+	}
+}
+
+func TestRateLimiterBackoff(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestsPerSecond float64
+		want              []time.Duration
+	}{
+		{name: "rate limiting enabled", requestsPerSecond: 1, want: []time.Duration{3 * time.Second, time.Second}},
+		{name: "rate limiting disabled", requestsPerSecond: 0, want: []time.Duration{3 * time.Second}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			limiter, clock := newTestRateLimiter(test.requestsPerSecond, 5)
+
+			limiter.Wait()
+			limiter.Backoff(3 * time.Second)
+
+			// retry waits for the backoff, next request is throttled by the refilled bucket
+			limiter.Wait()
+			limiter.Wait()
+
+			if len(clock.slept) != len(test.want) {
+				t.Fatalf("slept %v, want %v", clock.slept, test.want)
+			}
+			var total time.Duration
+			for i, delay := range test.want {
+				if clock.slept[i] != delay {
+					t.Fatalf("slept %v, want %v", clock.slept, test.want)
+				}
+				total += delay
+			}
+			if limiter.Waited() != total {
+				t.Fatalf("waited = %v, want %v", limiter.Waited(), total)
+			}
+		})
+	}
+}
+
+func TestRateLimiterBackoffDoesNotShorten(t *testing.T) {
+	limiter, clock := newTestRateLimiter(0, 1)
+
+	limiter.Backoff(5 * time.Second)
+	limiter.Backoff(time.Second)
+	limiter.Wait()
+
+	if len(clock.slept) != 1 || clock.slept[0] != 5*time.Second {
+		t.Fatalf("slept %v, want [5s]", clock.slept)
+	}
+}
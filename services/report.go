@@ -1,7 +1,9 @@
 package services
 
 import (
+	"fmt"
 	"log"
+	"math"
 	"pm-report/models"
 	"pm-report/utils"
 	"sort"
@@ -10,16 +12,18 @@ import (
 )
 
 type ReportService struct {
-	projectConfigService *ProjectConfigService
-	tempoService         *TempoService
-	tokens               []models.TokenTempoAppConfig
+	projectConfigService  *ProjectConfigService
+	worklogServiceFactory *WorklogServiceFactory
+	jiraService           *JiraService
+	tempoAppConfig        models.TempoAppConfig
 }
 
-func NewReportService(projectConfigService *ProjectConfigService, tempoService *TempoService, tokens []models.TokenTempoAppConfig) *ReportService {
+func NewReportService(projectConfigService *ProjectConfigService, worklogServiceFactory *WorklogServiceFactory, jiraService *JiraService, tempoAppConfig models.TempoAppConfig) *ReportService {
 	return &ReportService{
-		projectConfigService: projectConfigService,
-		tempoService:         tempoService,
-		tokens:               tokens,
+		projectConfigService:  projectConfigService,
+		worklogServiceFactory: worklogServiceFactory,
+		jiraService:           jiraService,
+		tempoAppConfig:        tempoAppConfig,
 	}
 }
 
@@ -33,18 +37,26 @@ func (s *ReportService) Create(dateFrom, dateTo time.Time) (*models.Report, erro
 
 	log.Println("Getting report started")
 
-	for _, token := range s.tokens {
+	// projects configured for any token, discovered ones are added while processing
+	knownProjectKeys := map[string]bool{}
+	for _, token := range s.tempoAppConfig.Tokens {
 		for _, projectKey := range utils.ToList(token.Projects) {
-			projectConfig := projectConfigWrapper.Get(projectKey)
-			if projectConfig == nil {
-				projectConfig = &models.ProjectConfig{Key: projectKey}
-			}
+			knownProjectKeys[projectKey] = true
+		}
+	}
 
-			project, err := s.getProject(token.Token, projectConfig, dateFrom, dateTo)
-			if err != nil {
-				return nil, err
-			}
-			projects = append(projects, *project)
+	for _, token := range s.tempoAppConfig.Tokens {
+		tokenProjects, err := s.getTokenProjects(token, projectConfigWrapper, knownProjectKeys, dateFrom, dateTo)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, tokenProjects...)
+	}
+
+	if s.jiraService.IsEnabled() {
+		err = s.enrichIssues(projects)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -55,6 +67,10 @@ func (s *ReportService) Create(dateFrom, dateTo time.Time) (*models.Report, erro
 		DateTo:   dateTo,
 		Projects: projects,
 	}
+	if s.jiraService.IsEnabled() {
+		report.Epics = s.getEpics(projects)
+		report.Variances = s.getVariances(projects)
+	}
 
 	err = s.projectConfigService.Save(projectConfigWrapper, report)
 	if err != nil {
@@ -64,13 +80,353 @@ func (s *ReportService) Create(dateFrom, dateTo time.Time) (*models.Report, erro
 	return report, nil
 }
 
-func (s *ReportService) getProject(token string, projectConfig *models.ProjectConfig, dateFrom, dateTo time.Time) (*models.Project, error) {
-	tempoResults, err := s.tempoService.GetTempoWorklogs(token, projectConfig.Key, dateFrom, dateTo)
+func (s *ReportService) getTokenProjects(token models.TokenTempoAppConfig, projectConfigWrapper *models.ProjectConfigWrapper, knownProjectKeys map[string]bool, dateFrom, dateTo time.Time) ([]models.Project, error) {
+	worklogService := s.worklogServiceFactory.Get(token)
+	var projectKeyToResults map[string][]models.TempoResult
+
+	fetchAll := s.tempoAppConfig.Strategy == models.OrganizationFetchStrategy || s.isDiscoveryEnabled()
+	if fetchAll {
+		var err error
+		projectKeyToResults, err = worklogService.GetAllWorklogs(token.Token, dateFrom, dateTo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	projectKeys := utils.ToList(token.Projects)
+	if s.isDiscoveryEnabled() {
+		projectKeys = s.discoverProjects(projectKeys, projectKeyToResults, knownProjectKeys)
+	}
+
+	var projects []models.Project
+
+	for _, projectKey := range projectKeys {
+		projectConfig := projectConfigWrapper.Get(projectKey)
+		if projectConfig == nil {
+			projectConfig = &models.ProjectConfig{Key: projectKey}
+		}
+		s.projectConfigService.Prefill(projectConfig)
+
+		tempoResults, ok := projectKeyToResults[projectKey]
+		if !ok && !fetchAll {
+			var err error
+			tempoResults, err = worklogService.GetWorklogs(token.Token, projectKey, dateFrom, dateTo)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		tempoResults, err := s.filterTempoResults(projectKey, tempoResults)
+		if err != nil {
+			return nil, err
+		}
+
+		project, err := s.getProject(tempoResults, projectConfig, projectConfigWrapper)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, *project)
+	}
+
+	s.logUnconfiguredProjects(projectKeyToResults, knownProjectKeys)
+
+	return projects, nil
+}
+
+// filterTempoResults keeps only worklogs of issues matching JQL filter of the project (if configured).
+func (s *ReportService) filterTempoResults(projectKey string, results []models.TempoResult) ([]models.TempoResult, error) {
+	issueKeys, ok, err := s.jiraService.GetFilteredIssueKeys(projectKey)
+	if err != nil || !ok {
+		return results, err
+	}
+
+	var filtered []models.TempoResult
+	for _, result := range results {
+		if issueKeys[result.Issue.Key] {
+			filtered = append(filtered, result)
+		}
+	}
+
+	log.Println("Filtered tempo report for", projectKey, "project:", len(filtered), "of", len(results), "records")
+
+	return filtered, nil
+}
+
+func (s *ReportService) isDiscoveryEnabled() bool {
+	mode := s.tempoAppConfig.Discovery.Mode
+	return mode == models.WarnDiscoveryMode || mode == models.IncludeDiscoveryMode
+}
+
+// discoverProjects lists projects having worklogs in the period and,
+// in include mode, appends the allowed ones to the configured projects.
+func (s *ReportService) discoverProjects(projectKeys []string, projectKeyToResults map[string][]models.TempoResult, knownProjectKeys map[string]bool) []string {
+	discovered := make([]string, 0, len(projectKeyToResults))
+	for projectKey := range projectKeyToResults {
+		discovered = append(discovered, projectKey)
+	}
+	sort.Strings(discovered)
+
+	log.Println("Discovered projects with worklogs:", strings.Join(discovered, ", "))
+
+	if s.tempoAppConfig.Discovery.Mode != models.IncludeDiscoveryMode {
+		return projectKeys
+	}
+
+	allow := utils.ToList(s.tempoAppConfig.Discovery.Allow)
+	deny := utils.ToList(s.tempoAppConfig.Discovery.Deny)
+
+	result := projectKeys
+	for _, projectKey := range discovered {
+		if knownProjectKeys[projectKey] {
+			continue
+		}
+		if len(allow) > 0 && !utils.Contains(allow, projectKey) {
+			continue
+		}
+		if utils.Contains(deny, projectKey) {
+			continue
+		}
+
+		log.Println("Discovered project is included into report:", projectKey)
+		knownProjectKeys[projectKey] = true
+		result = append(result, projectKey)
+	}
+
+	return result
+}
+
+func (s *ReportService) logUnconfiguredProjects(projectKeyToResults map[string][]models.TempoResult, knownProjectKeys map[string]bool) {
+	var unconfigured []string
+	for projectKey := range projectKeyToResults {
+		if !knownProjectKeys[projectKey] {
+			unconfigured = append(unconfigured, projectKey)
+		}
+	}
+	sort.Strings(unconfigured)
+
+	for _, projectKey := range unconfigured {
+		log.Println("Warning: project has worklogs but is not configured:", projectKey,
+			fmt.Sprintf("(%d records)", len(projectKeyToResults[projectKey])))
+	}
+}
+
+func (s *ReportService) enrichIssues(projects []models.Project) error {
+	var issueKeys []string
+	for _, project := range projects {
+		for _, user := range project.Users {
+			for _, issue := range user.Issues {
+				issueKeys = append(issueKeys, issue.Key)
+			}
+		}
+	}
+
+	keyToJiraIssue, err := s.jiraService.GetIssues(issueKeys)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	// parents which are not epics (e.g. stories of sub-tasks) are needed to find epics
+	var parentKeys []string
+	for _, jiraIssue := range keyToJiraIssue {
+		parent := jiraIssue.Fields.Parent
+		if parent != nil && parent.Fields.IssueType.HierarchyLevel < jiraEpicHierarchyLevel {
+			parentKeys = append(parentKeys, parent.Key)
+		}
+	}
+
+	keyToJiraParent, err := s.jiraService.GetIssues(parentKeys)
+	if err != nil {
+		return err
+	}
+
+	for i := range projects {
+		for j := range projects[i].Users {
+			issues := projects[i].Users[j].Issues
+			for k := range issues {
+				jiraIssue, ok := keyToJiraIssue[issues[k].Key]
+				if !ok {
+					continue
+				}
+
+				fields := jiraIssue.Fields
+				issues[k].Summary = fields.Summary
+				issues[k].Type = fields.IssueType.Name
+				issues[k].Status = fields.Status.Name
+				if fields.Priority != nil {
+					issues[k].Priority = fields.Priority.Name
+				}
+				if fields.Assignee != nil {
+					issues[k].Assignee = fields.Assignee.DisplayName
+				}
+				issues[k].EpicKey, issues[k].Epic = s.getEpic(jiraIssue, keyToJiraParent)
+				if fields.TimeOriginalEstimate != nil {
+					issues[k].OriginalEstimateSeconds = *fields.TimeOriginalEstimate
+				}
+				if fields.TimeEstimate != nil {
+					issues[k].RemainingEstimateSeconds = *fields.TimeEstimate
+				}
+				if fields.TimeSpent != nil {
+					issues[k].TotalSpentSeconds = *fields.TimeSpent
+				}
+				issues[k].Components = nil
+				for _, component := range fields.Components {
+					issues[k].Components = append(issues[k].Components, component.Name)
+				}
+				issues[k].Labels = fields.Labels
+				issues[k].ProjectKey = fields.Project.Key
+				issues[k].Closed = fields.Status.StatusCategory.Key == jiraDoneStatusCategory
+				if len(fields.ResolutionDate) >= len(dateFormat) {
+					issues[k].ResolutionDate = fields.ResolutionDate[:len(dateFormat)]
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *ReportService) getEpic(jiraIssue models.JiraIssue, keyToJiraParent map[string]models.JiraIssue) (string, string) {
+	if jiraIssue.Fields.IssueType.HierarchyLevel == jiraEpicHierarchyLevel {
+		return jiraIssue.Key, jiraIssue.Fields.Summary
+	}
+
+	parent := jiraIssue.Fields.Parent
+	if parent == nil {
+		return "", ""
+	}
+	if parent.Fields.IssueType.HierarchyLevel == jiraEpicHierarchyLevel {
+		return parent.Key, parent.Fields.Summary
+	}
+
+	jiraParent, ok := keyToJiraParent[parent.Key]
+	if ok && jiraParent.Fields.Parent != nil && jiraParent.Fields.Parent.Fields.IssueType.HierarchyLevel == jiraEpicHierarchyLevel {
+		return jiraParent.Fields.Parent.Key, jiraParent.Fields.Parent.Fields.Summary
+	}
+
+	return "", ""
+}
+
+// getEpics rolls up hours and cost of each project by epics broken down by users,
+// issues without epic are collected into "No epic" bucket.
+func (s *ReportService) getEpics(projects []models.Project) []models.ProjectEpics {
+	var projectEpics []models.ProjectEpics
+
+	for _, project := range projects {
+		epicKeyToEpic := map[string]*models.Epic{}
+
+		for _, user := range project.Users {
+			epicKeyToEfforts := map[string][]models.Effort{}
+
+			for _, issue := range user.Issues {
+				if _, ok := epicKeyToEpic[issue.EpicKey]; !ok {
+					summary := issue.Epic
+					if len(issue.EpicKey) == 0 {
+						summary = "No epic"
+					}
+					epicKeyToEpic[issue.EpicKey] = &models.Epic{Key: issue.EpicKey, Summary: summary}
+				}
+
+				epicKeyToEfforts[issue.EpicKey] = append(epicKeyToEfforts[issue.EpicKey], issue.Efforts...)
+			}
+
+			for epicKey, efforts := range epicKeyToEfforts {
+				seconds := 0
+				for _, effort := range efforts {
+					seconds += effort.TimeSpentSeconds
+				}
+
+				epicUser := models.EpicUser{
+					Name:     user.Name,
+					Rate:     user.GetBlendedRate(efforts),
+					Currency: user.Currency,
+					Hours:    utils.ToHours(seconds),
+					Cost:     user.GetCost(efforts),
+				}
+
+				epic := epicKeyToEpic[epicKey]
+				if len(epic.Users) == 0 {
+					epic.Currency = epicUser.Currency
+				} else if !strings.EqualFold(epic.Currency, epicUser.Currency) {
+					epic.MixedCurrencies = true
+				}
+				epic.Users = append(epic.Users, epicUser)
+				epic.Hours += epicUser.Hours
+				epic.Cost += epicUser.Cost
+			}
+		}
+
+		epics := make([]models.Epic, 0, len(epicKeyToEpic))
+		for _, epic := range epicKeyToEpic {
+			if epic.MixedCurrencies {
+				epic.Cost = 0
+				epic.Currency = ""
+			}
+			epics = append(epics, *epic)
+		}
+		sort.Slice(epics, func(i, j int) bool {
+			if len(epics[i].Key) == 0 || len(epics[j].Key) == 0 {
+				return len(epics[j].Key) == 0 && len(epics[i].Key) > 0 // "No epic" goes last
+			}
+			return epics[i].Key < epics[j].Key
+		})
+
+		projectEpics = append(projectEpics, models.ProjectEpics{
+			ProjectKey: project.Key,
+			Epics:      epics,
+		})
+	}
+
+	return projectEpics
+}
+
+// getVariances lists issues whose total logged time exceeds original estimate sorted by overrun.
+func (s *ReportService) getVariances(projects []models.Project) []models.IssueVariance {
+	var variances []models.IssueVariance
+
+	for _, project := range projects {
+		issueKeyToIssue := map[string]models.Issue{}
+		issueKeyToSeconds := map[string]int{}
+
+		for _, user := range project.Users {
+			for _, issue := range user.Issues {
+				issueKeyToIssue[issue.Key] = issue
+				for _, effort := range issue.Efforts {
+					issueKeyToSeconds[issue.Key] += effort.TimeSpentSeconds
+				}
+			}
+		}
+
+		for issueKey, issue := range issueKeyToIssue {
+			estimate := issue.OriginalEstimateSeconds
+			if estimate <= 0 || issue.TotalSpentSeconds <= estimate {
+				continue
+			}
+
+			variances = append(variances, models.IssueVariance{
+				ProjectKey:     project.Key,
+				Key:            issueKey,
+				Summary:        issue.Summary,
+				EstimateHours:  utils.ToHours(estimate),
+				RemainingHours: utils.ToHours(issue.RemainingEstimateSeconds),
+				PeriodHours:    utils.ToHours(issueKeyToSeconds[issueKey]),
+				TotalHours:     utils.ToHours(issue.TotalSpentSeconds),
+				Overrun:        math.Round(float64(issue.TotalSpentSeconds-estimate)*10000/float64(estimate)) / 100,
+			})
+		}
 	}
 
-	users, err := s.getUsers(tempoResults, projectConfig)
+	sort.Slice(variances, func(i, j int) bool {
+		if variances[i].Overrun != variances[j].Overrun {
+			return variances[i].Overrun > variances[j].Overrun
+		}
+		return variances[i].Key < variances[j].Key
+	})
+
+	return variances
+}
+
+func (s *ReportService) getProject(tempoResults []models.TempoResult, projectConfig *models.ProjectConfig, projectConfigWrapper *models.ProjectConfigWrapper) (*models.Project, error) {
+	users, err := s.getUsers(tempoResults, projectConfig, projectConfigWrapper)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +441,7 @@ func (s *ReportService) getProject(token string, projectConfig *models.ProjectCo
 	return &project, nil
 }
 
-func (s *ReportService) getUsers(results []models.TempoResult, projectConfig *models.ProjectConfig) ([]models.User, error) {
+func (s *ReportService) getUsers(results []models.TempoResult, projectConfig *models.ProjectConfig, projectConfigWrapper *models.ProjectConfigWrapper) ([]models.User, error) {
 	userIdToTempoResult := map[string][]models.TempoResult{} // group tempo results by account id
 
 	for _, result := range results {
@@ -106,15 +462,29 @@ func (s *ReportService) getUsers(results []models.TempoResult, projectConfig *mo
 		}
 
 		author := userResults[0].Author
-		userConfig := projectConfig.UserNameToConfig[author.DisplayName]
+		userConfig, rateFromCard := s.getUserConfig(author, projectConfig, projectConfigWrapper)
 
 		user := models.User{
-			AccountId: author.AccountId,
-			Name:      author.DisplayName,
-			Position:  userConfig.Position,
-			Rate:      userConfig.Rate,
-			Issues:    issues,
+			AccountId:   author.AccountId,
+			Email:       userConfig.Email,
+			Name:        author.DisplayName,
+			Position:    userConfig.Position,
+			Currency:    userConfig.Currency,
+			Issues:      issues,
+			LineManager: userConfig.LineManager,
+			Team:        userConfig.Team,
+			BaseRate:    userConfig.Rate,
+
+			RateFromCard: rateFromCard,
+			RateHistory:  userConfig.RateHistory,
+		}
+
+		var efforts []models.Effort
+		for _, issue := range issues {
+			efforts = append(efforts, issue.Efforts...)
 		}
+		user.Rate = user.GetBlendedRate(efforts)
+
 		users = append(users, user)
 	}
 
@@ -125,6 +495,30 @@ func (s *ReportService) getUsers(results []models.TempoResult, projectConfig *mo
 	return users, nil
 }
 
+// getUserConfig resolves effective user values: project overrides first, then people registry defaults,
+// then rate card by position if there is still no rate. True is returned if rate is taken from rate card.
+func (s *ReportService) getUserConfig(author models.TempoAuthor, projectConfig *models.ProjectConfig, projectConfigWrapper *models.ProjectConfigWrapper) (models.UserConfig, bool) {
+	userConfig := projectConfig.GetUser(author.AccountId, author.DisplayName)
+	if userConfig == nil {
+		userConfig = &models.UserConfig{}
+	}
+
+	effectiveUserConfig := userConfig.WithDefaults(projectConfigWrapper.GetPerson(author.AccountId, author.DisplayName))
+	if len(effectiveUserConfig.Position) == 0 || effectiveUserConfig.Rate != 0 || len(effectiveUserConfig.RateHistory) > 0 {
+		return effectiveUserConfig, false
+	}
+
+	rateCard := projectConfigWrapper.GetRateCard(effectiveUserConfig.Position, projectConfig.Key, projectConfig.Owner)
+	if rateCard == nil {
+		return effectiveUserConfig, false
+	}
+
+	effectiveUserConfig.Rate = rateCard.Rate
+	effectiveUserConfig.Currency = rateCard.Currency // currency of the rate
+
+	return effectiveUserConfig, true
+}
+
 func (s *ReportService) getIssues(results []models.TempoResult) ([]models.Issue, error) {
 	issueKeyToResults := map[string][]models.TempoResult{} // group tempo results by issue id
 
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"pm-report/models"
+	"testing"
+)
+
+func TestReportServiceGetUserConfig(t *testing.T) {
+	projectConfigWrapper := &models.ProjectConfigWrapper{
+		People: []models.UserConfig{
+			{AccountId: "a1", Name: "Alice", Position: "Developer", Rate: 40, Currency: "EUR"},
+			{AccountId: "b2", Name: "Bob", Position: "QA"},
+			{AccountId: "c3", Name: "Carol", Position: "Designer"},
+		},
+		RateCards: []models.RateCardConfig{{Position: "QA", Rate: 25, Currency: "GBP"}},
+	}
+
+	tests := []struct {
+		name             string
+		projectUser      models.UserConfig
+		author           models.TempoAuthor
+		wantRate         float64
+		wantCurrency     string
+		wantRateFromCard bool
+	}{
+		{name: "project rate", projectUser: models.UserConfig{AccountId: "a1", Rate: 50, Currency: "USD"},
+			author: models.TempoAuthor{AccountId: "a1", DisplayName: "Alice"}, wantRate: 50, wantCurrency: "USD"},
+		{name: "project rate without currency", projectUser: models.UserConfig{AccountId: "a1", Rate: 50},
+			author: models.TempoAuthor{AccountId: "a1", DisplayName: "Alice"}, wantRate: 50, wantCurrency: ""},
+		{name: "registry rate with its currency", projectUser: models.UserConfig{AccountId: "a1", Currency: "USD"},
+			author: models.TempoAuthor{AccountId: "a1", DisplayName: "Alice"}, wantRate: 40, wantCurrency: "EUR"},
+		{name: "user only in registry", author: models.TempoAuthor{AccountId: "a1", DisplayName: "Alice"}, wantRate: 40, wantCurrency: "EUR"},
+		{name: "rate card with its currency", projectUser: models.UserConfig{AccountId: "b2", Currency: "USD"},
+			author: models.TempoAuthor{AccountId: "b2", DisplayName: "Bob"}, wantRate: 25, wantCurrency: "GBP", wantRateFromCard: true},
+		{name: "no rate card for position", author: models.TempoAuthor{AccountId: "c3", DisplayName: "Carol"}, wantRate: 0},
+		{name: "unknown user", author: models.TempoAuthor{AccountId: "d4", DisplayName: "Dave"}, wantRate: 0},
+	}
+
+	reportService := NewReportService(nil, nil, nil, models.TempoAppConfig{})
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			projectConfig := &models.ProjectConfig{Key: "ABC"}
+			if len(test.projectUser.AccountId) > 0 {
+				projectConfig.Users = []models.UserConfig{test.projectUser}
+			}
+
+			userConfig, rateFromCard := reportService.getUserConfig(test.author, projectConfig, projectConfigWrapper)
+			if userConfig.Rate != test.wantRate || userConfig.Currency != test.wantCurrency || rateFromCard != test.wantRateFromCard {
+				t.Fatalf("getUserConfig() = %+v (from card %v), want rate %v %s (from card %v)",
+					userConfig, rateFromCard, test.wantRate, test.wantCurrency, test.wantRateFromCard)
+			}
+		})
+	}
+}
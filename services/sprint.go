@@ -0,0 +1,169 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"pm-report/models"
+	"pm-report/utils"
+	"strconv"
+	"time"
+)
+
+type SprintService struct {
+	jiraService *JiraService
+}
+
+func NewSprintService(jiraService *JiraService) *SprintService {
+	return &SprintService{jiraService: jiraService}
+}
+
+// Get resolves the sprint of the board (or the last closed one) and derives report period from sprint dates.
+func (s *SprintService) Get(boardId int, sprintArg string) (*models.Sprint, error) {
+	if !s.jiraService.IsEnabled() {
+		return nil, errors.New("error: sprint report requires jira integration")
+	}
+
+	jiraSprint, err := s.getJiraSprint(boardId, sprintArg)
+	if err != nil {
+		return nil, err
+	}
+
+	dateFrom, err := s.parseDate(jiraSprint.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate := jiraSprint.CompleteDate
+	if len(endDate) == 0 {
+		endDate = jiraSprint.EndDate
+	}
+	dateTo, err := s.parseDate(endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	sprint := &models.Sprint{
+		Id:       jiraSprint.Id,
+		Name:     jiraSprint.Name,
+		DateFrom: *dateFrom,
+		DateTo:   *dateTo,
+	}
+	log.Println("Sprint is resolved:", sprint.Name, sprint.DateFrom.Format(dateFormat), "-", sprint.DateTo.Format(dateFormat))
+
+	return sprint, nil
+}
+
+func (s *SprintService) getJiraSprint(boardId int, sprintArg string) (*models.JiraSprint, error) {
+	if sprintArg != models.LastClosedSprint {
+		sprintId, err := strconv.Atoi(sprintArg)
+		if err != nil {
+			return nil, err
+		}
+		jiraSprint, err := s.jiraService.GetSprint(sprintId)
+		if err != nil {
+			return nil, err
+		}
+		if jiraSprint.OriginBoardId != boardId {
+			return nil, fmt.Errorf("error: sprint %d does not belong to board %d", sprintId, boardId)
+		}
+		return jiraSprint, nil
+	}
+
+	sprints, err := s.jiraService.GetSprints(boardId, "closed")
+	if err != nil {
+		return nil, err
+	}
+
+	var lastSprint *models.JiraSprint
+	var lastCompleteDate time.Time
+	for i := range sprints {
+		completeDate, err := s.parseDateTime(sprints[i].CompleteDate)
+		if err != nil {
+			log.Println("Sprint", sprints[i].Name, "is skipped, complete date cannot be parsed:", err)
+			continue
+		}
+		if lastSprint == nil || completeDate.After(lastCompleteDate) {
+			lastSprint = &sprints[i]
+			lastCompleteDate = *completeDate
+		}
+	}
+	if lastSprint == nil {
+		return nil, fmt.Errorf("error: no closed sprints found for board %d", boardId)
+	}
+
+	return lastSprint, nil
+}
+
+func (s *SprintService) parseDate(value string) (*time.Time, error) {
+	dateTime, err := s.parseDateTime(value)
+	if err != nil {
+		return nil, err
+	}
+
+	date := time.Date(dateTime.Year(), dateTime.Month(), dateTime.Day(), 0, 0, 0, 0, time.UTC)
+	return &date, nil
+}
+
+// parseDateTime parses sprint timestamp, dates of closed sprints are compared as times
+// because their offsets may differ.
+func (s *SprintService) parseDateTime(value string) (*time.Time, error) {
+	if len(value) == 0 {
+		return nil, errors.New("error: sprint has no dates")
+	}
+
+	dateTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dateTime, nil
+}
+
+// Fill splits hours of each user into hours logged on sprint issues and on other issues.
+func (s *SprintService) Fill(sprint *models.Sprint, report *models.Report) error {
+	issueKeys, err := s.jiraService.SearchIssueKeys(fmt.Sprintf("sprint = %d", sprint.Id))
+	if err != nil {
+		return err
+	}
+
+	sprintIssueKeys := map[string]bool{}
+	for _, issueKey := range issueKeys {
+		sprintIssueKeys[issueKey] = true
+	}
+	log.Println("Fetched sprint issues:", len(sprintIssueKeys))
+
+	sprint.Users = nil
+	for _, project := range report.Projects {
+		for _, user := range project.Users {
+			var sprintEfforts []models.Effort
+			sprintSeconds := 0
+			otherSeconds := 0
+
+			for _, issue := range user.Issues {
+				for _, effort := range issue.Efforts {
+					if sprintIssueKeys[issue.Key] {
+						sprintEfforts = append(sprintEfforts, effort)
+						sprintSeconds += effort.TimeSpentSeconds
+					} else {
+						otherSeconds += effort.TimeSpentSeconds
+					}
+				}
+			}
+
+			sprint.Users = append(sprint.Users, models.SprintUser{
+				ProjectKey:  project.Key,
+				Name:        user.Name,
+				Rate:        user.GetBlendedRate(sprintEfforts),
+				Currency:    user.Currency,
+				SprintHours: utils.ToHours(sprintSeconds),
+				OtherHours:  utils.ToHours(otherSeconds),
+				Cost:        user.GetCost(sprintEfforts),
+			})
+		}
+	}
+
+	report.Sprint = sprint
+
+	return nil
+}
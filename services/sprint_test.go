@@ -0,0 +1,63 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"pm-report/models"
+	"testing"
+)
+
+func newJiraSprintServer(t *testing.T) *httptest.Server {
+	sprints := []models.JiraSprint{
+		// later than sprint 12 although the string is lower because of the offset
+		{Id: 11, Name: "Sprint 11", State: "closed", StartDate: "2026-09-01T09:00:00.000Z",
+			EndDate: "2026-09-15T09:00:00.000Z", CompleteDate: "2026-09-15T20:00:00.000-08:00", OriginBoardId: 5},
+		{Id: 12, Name: "Sprint 12", State: "closed", StartDate: "2026-09-01T09:00:00.000Z",
+			EndDate: "2026-09-16T09:00:00.000Z", CompleteDate: "2026-09-16T02:00:00.000Z", OriginBoardId: 5},
+	}
+
+	return newTestJiraServer(t, map[string]testJiraHandler{
+		"/rest/agile/1.0/board/5/sprint": func(request *http.Request) (interface{}, int) {
+			return models.JiraSprintsResponse{Values: sprints, IsLast: true}, 0
+		},
+		"/rest/agile/1.0/sprint/11": func(request *http.Request) (interface{}, int) {
+			return sprints[0], 0
+		},
+	})
+}
+
+func TestSprintGet(t *testing.T) {
+	server := newJiraSprintServer(t)
+	sprintService := NewSprintService(NewJiraService(models.JiraAppConfig{Url: server.URL}))
+
+	tests := []struct {
+		name      string
+		boardId   int
+		sprintArg string
+		wantId    int
+		wantErr   bool
+	}{
+		{name: "last closed sprint by complete time", boardId: 5, sprintArg: models.LastClosedSprint, wantId: 11},
+		{name: "sprint of the board", boardId: 5, sprintArg: "11", wantId: 11},
+		{name: "sprint of another board", boardId: 6, sprintArg: "11", wantErr: true},
+		{name: "unknown board", boardId: 6, sprintArg: models.LastClosedSprint, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sprint, err := sprintService.Get(test.boardId, test.sprintArg)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("sprint = %v, want error", sprint)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sprint.Id != test.wantId {
+				t.Fatalf("sprint id = %d, want %d", sprint.Id, test.wantId)
+			}
+		})
+	}
+}
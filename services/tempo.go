@@ -8,36 +8,92 @@ import (
 	"log"
 	"net/http"
 	"pm-report/models"
+	"pm-report/utils"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
 	dateFormat = "2006-01-02"
+
+	tempoMaxRetries     = 3           // retries of throttled (429) requests
+	tempoDefaultBackoff = time.Second // used if throttled response has no Retry-After header
 )
 
 type TempoService struct {
-	worklogsUrlTemplate string
+	worklogsUrlTemplate        string
+	projectWorklogsUrlTemplate string
+	rateLimiters               *RateLimiterRegistry
 }
 
-func NewTempoService(url string) *TempoService {
+func NewTempoService(url string, rateLimiters *RateLimiterRegistry) *TempoService {
 	return &TempoService{
-		worklogsUrlTemplate: url + "/core/3/worklogs?project=%s&from=%s&to=%s&offset=%d&limit=%d",
+		worklogsUrlTemplate:        url + "/core/3/worklogs?from=%s&to=%s&offset=%d&limit=%d",
+		projectWorklogsUrlTemplate: url + "/core/3/worklogs?project=%s&from=%s&to=%s&offset=%d&limit=%d",
+		rateLimiters:               rateLimiters,
+	}
+}
+
+func (s *TempoService) GetWorklogs(token, projectKey string, dateFrom, dateTo time.Time) ([]models.TempoResult, error) {
+	return s.fetchTempoResults(token, projectKey+" project", func(offset, limit int) string {
+		return fmt.Sprintf(s.projectWorklogsUrlTemplate,
+			projectKey,
+			dateFrom.Format(dateFormat),
+			dateTo.Format(dateFormat),
+			offset,
+			limit)
+	})
+}
+
+// GetAllWorklogs fetches worklogs of all projects available for the token,
+// results are grouped by project key taken from the issue key.
+func (s *TempoService) GetAllWorklogs(token string, dateFrom, dateTo time.Time) (map[string][]models.TempoResult, error) {
+	tempoResults, err := s.fetchTempoResults(token, "organization", func(offset, limit int) string {
+		return fmt.Sprintf(s.worklogsUrlTemplate,
+			dateFrom.Format(dateFormat),
+			dateTo.Format(dateFormat),
+			offset,
+			limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	projectKeyToResults := map[string][]models.TempoResult{}
+	for _, result := range tempoResults {
+		projectKey := utils.ToProjectKey(result.Issue.Key)
+		projectKeyToResults[projectKey] = append(projectKeyToResults[projectKey], result)
 	}
+
+	return projectKeyToResults, nil
 }
 
-func (s *TempoService) GetTempoWorklogs(token, projectKey string, dateFrom, dateTo time.Time) ([]models.TempoResult, error) {
+// CheckAccess requests a single worklog to make sure the token is able to read worklogs
+// of the project, or of any project when the project key is empty.
+func (s *TempoService) CheckAccess(token, projectKey string, dateFrom, dateTo time.Time) error {
+	url := fmt.Sprintf(s.worklogsUrlTemplate, dateFrom.Format(dateFormat), dateTo.Format(dateFormat), 0, 1)
+	if len(projectKey) > 0 {
+		url = fmt.Sprintf(s.projectWorklogsUrlTemplate, projectKey, dateFrom.Format(dateFormat), dateTo.Format(dateFormat), 0, 1)
+	}
+
+	_, err := s.fetchTempoResponse(token, url)
+	return err
+}
+
+func (s *TempoService) fetchTempoResults(token, subject string, urlFunc func(offset, limit int) string) ([]models.TempoResult, error) {
 	var tempoResults []models.TempoResult
 	offset := 0
 	limit := 100
 
 	for {
-		response, err := s.fetchTempoResponse(token, projectKey, dateFrom, dateTo, offset, limit)
+		response, err := s.fetchTempoResponse(token, urlFunc(offset, limit))
 		if err != nil {
 			return nil, err
 		}
 		tempoResults = append(tempoResults, response.Results...)
 
-		log.Println("Fetched tempo report for", projectKey, "project:", response.Metadata.Count, "records")
+		log.Println("Fetched tempo report for", subject+":", response.Metadata.Count, "records")
 
 		if response.Metadata.Count < response.Metadata.Limit {
 			break
@@ -48,19 +104,35 @@ func (s *TempoService) GetTempoWorklogs(token, projectKey string, dateFrom, date
 	return tempoResults, nil
 }
 
-func (s *TempoService) fetchTempoResponse(token, projectKey string, dateFrom, dateTo time.Time, offset, limit int) (*models.TempoResponse, error) {
-	client := http.Client{Timeout: time.Second * 60}
+// fetchTempoResponse requests Tempo API, throttled requests (429 Too Many Requests) are retried
+// after the delay from Retry-After header, other requests with the token wait for the same delay.
+func (s *TempoService) fetchTempoResponse(token, url string) (*models.TempoResponse, error) {
+	for attempt := 0; ; attempt++ {
+		s.rateLimiters.Wait(token)
 
-	url := fmt.Sprintf(s.worklogsUrlTemplate,
-		projectKey,
-		dateFrom.Format(dateFormat),
-		dateTo.Format(dateFormat),
-		offset,
-		limit)
+		tempoResponse, retryAfter, err := s.doTempoRequest(token, url)
+		if err != nil {
+			return nil, err
+		}
+		if tempoResponse != nil {
+			return tempoResponse, nil
+		}
+
+		if attempt >= tempoMaxRetries {
+			return nil, errors.New("Tempo error: " + http.StatusText(http.StatusTooManyRequests))
+		}
+		log.Println("Tempo request is throttled, retrying in", retryAfter)
+		s.rateLimiters.Backoff(token, retryAfter)
+	}
+}
+
+// doTempoRequest makes a single request, nil response with delay is returned if the request is throttled.
+func (s *TempoService) doTempoRequest(token, url string) (*models.TempoResponse, time.Duration, error) {
+	client := http.Client{Timeout: time.Second * 60}
 
 	request, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	request.Header.Set("Authorization", "Bearer "+token)
@@ -68,7 +140,7 @@ func (s *TempoService) fetchTempoResponse(token, projectKey string, dateFrom, da
 
 	response, err := client.Do(request)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	if response.Body != nil {
@@ -81,20 +153,33 @@ func (s *TempoService) fetchTempoResponse(token, projectKey string, dateFrom, da
 		}()
 	}
 
+	if response.StatusCode == http.StatusTooManyRequests {
+		return nil, s.getRetryAfter(response), nil
+	}
+
 	if response.StatusCode != 200 {
-		return nil, errors.New("Tempo error: " + response.Status)
+		return nil, 0, errors.New("Tempo error: " + response.Status)
 	}
 
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	tempoResponse := &models.TempoResponse{}
 	err = json.Unmarshal(body, tempoResponse)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return tempoResponse, nil
+	return tempoResponse, 0, nil
+}
+
+// getRetryAfter reads delay in seconds from Retry-After header.
+func (s *TempoService) getRetryAfter(response *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(response.Header.Get("Retry-After")))
+	if err != nil || seconds <= 0 {
+		return tempoDefaultBackoff
+	}
+	return time.Duration(seconds) * time.Second
 }
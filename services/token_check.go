@@ -0,0 +1,98 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"pm-report/models"
+	"pm-report/utils"
+	"text/tabwriter"
+	"time"
+)
+
+type TokenCheckService struct {
+	worklogServiceFactory *WorklogServiceFactory
+	tokens                []models.TokenTempoAppConfig
+}
+
+func NewTokenCheckService(worklogServiceFactory *WorklogServiceFactory, tokens []models.TokenTempoAppConfig) *TokenCheckService {
+	return &TokenCheckService{
+		worklogServiceFactory: worklogServiceFactory,
+		tokens:                tokens,
+	}
+}
+
+func (s *TokenCheckService) Check() []models.TokenCheck {
+	dateTo := time.Now()
+	dateFrom := dateTo.AddDate(0, -1, 0)
+
+	var tokenChecks []models.TokenCheck
+
+	for _, token := range s.tokens {
+		worklogService := s.worklogServiceFactory.Get(token)
+		tokenCheck := models.TokenCheck{Token: utils.Mask(token.Token)}
+
+		err := worklogService.CheckAccess(token.Token, "", dateFrom, dateTo)
+		if err != nil {
+			tokenCheck.Error = err.Error()
+		} else {
+			tokenCheck.Valid = true
+
+			for _, projectKey := range utils.ToList(token.Projects) {
+				projectCheck := models.ProjectTokenCheck{Key: projectKey}
+
+				err = worklogService.CheckAccess(token.Token, projectKey, dateFrom, dateTo)
+				if err != nil {
+					projectCheck.Error = err.Error()
+				} else {
+					projectCheck.Readable = true
+				}
+
+				tokenCheck.Projects = append(tokenCheck.Projects, projectCheck)
+			}
+		}
+
+		log.Println("Checked token", tokenCheck.Token, "valid:", tokenCheck.Valid)
+		tokenChecks = append(tokenChecks, tokenCheck)
+	}
+
+	return tokenChecks
+}
+
+func (s *TokenCheckService) Print(w io.Writer, tokenChecks []models.TokenCheck) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, err := fmt.Fprintln(tw, "TOKEN\tPROJECT\tSTATUS\tDETAILS")
+	if err != nil {
+		return err
+	}
+
+	for _, tokenCheck := range tokenChecks {
+		if !tokenCheck.Valid {
+			_, err = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", tokenCheck.Token, "-", "INVALID", tokenCheck.Error)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, err = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", tokenCheck.Token, "-", "VALID", "expiry is not exposed by API")
+		if err != nil {
+			return err
+		}
+
+		for _, projectCheck := range tokenCheck.Projects {
+			status := "READABLE"
+			if !projectCheck.Readable {
+				status = "UNREADABLE"
+			}
+
+			_, err = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", tokenCheck.Token, projectCheck.Key, status, projectCheck.Error)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tw.Flush()
+}
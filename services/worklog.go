@@ -0,0 +1,89 @@
+package services
+
+import (
+	"pm-report/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WorklogService fetches worklogs from a worklog source (Tempo or Jira),
+// worklogs are returned in Tempo format to be aggregated the same way.
+type WorklogService interface {
+	GetWorklogs(token, projectKey string, dateFrom, dateTo time.Time) ([]models.TempoResult, error)
+	GetAllWorklogs(token string, dateFrom, dateTo time.Time) (map[string][]models.TempoResult, error)
+	CheckAccess(token, projectKey string, dateFrom, dateTo time.Time) error
+}
+
+// WorklogServiceFactory creates worklog services per token source and base url, so tokens of different
+// Tempo instances (e.g. EU- and US-hosted) or Jira sites can be used in a single run.
+// Rate limiters are shared by all created services.
+type WorklogServiceFactory struct {
+	mutex         sync.Mutex
+	tempoUrl      string
+	jiraAppConfig models.JiraAppConfig
+	rateLimiters  *RateLimiterRegistry
+	keyToService  map[string]WorklogService
+}
+
+func NewWorklogServiceFactory(tempoAppConfig models.TempoAppConfig, jiraAppConfig models.JiraAppConfig) *WorklogServiceFactory {
+	return &WorklogServiceFactory{
+		tempoUrl:      tempoAppConfig.Url,
+		jiraAppConfig: jiraAppConfig,
+		rateLimiters:  NewRateLimiterRegistry(tempoAppConfig.RateLimit),
+		keyToService:  map[string]WorklogService{},
+	}
+}
+
+func (s *WorklogServiceFactory) Get(token models.TokenTempoAppConfig) WorklogService {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if token.Source == models.JiraWorklogSource {
+		return s.getJiraWorklogService(token)
+	}
+
+	url := s.tempoUrl
+	if len(token.Url) > 0 {
+		url = token.Url
+	}
+	url = strings.TrimRight(url, "/")
+
+	key := models.TempoWorklogSource + ":" + url
+	if worklogService, ok := s.keyToService[key]; ok {
+		return worklogService
+	}
+
+	worklogService := NewTempoService(url, s.rateLimiters)
+	s.keyToService[key] = worklogService
+
+	return worklogService
+}
+
+func (s *WorklogServiceFactory) getJiraWorklogService(token models.TokenTempoAppConfig) WorklogService {
+	jiraAppConfig := models.JiraAppConfig{
+		Url:   s.jiraAppConfig.Url,
+		Email: s.jiraAppConfig.Email,
+		Token: token.Token,
+	}
+	if len(token.Url) > 0 {
+		jiraAppConfig.Url = token.Url
+	}
+	if len(token.Email) > 0 {
+		jiraAppConfig.Email = token.Email
+	}
+
+	key := models.JiraWorklogSource + ":" + jiraAppConfig.Url + ":" + jiraAppConfig.Email + ":" + token.Token
+	if worklogService, ok := s.keyToService[key]; ok {
+		return worklogService
+	}
+
+	worklogService := NewJiraWorklogService(NewJiraService(jiraAppConfig))
+	s.keyToService[key] = worklogService
+
+	return worklogService
+}
+
+func (s *WorklogServiceFactory) LogStats() {
+	s.rateLimiters.LogStats()
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// currencySymbols maps common currency codes to symbols, other codes are shown as is.
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"CNY": "¥",
+	"INR": "₹",
+	"UAH": "₴",
+	"PLN": "zł",
+}
+
+func ToHours(seconds int) float64 {
+	value := float64(seconds) / 3600
+	return math.Round(value*100) / 100
+}
+
+// FormatCurrency formats value with currency symbol, empty code is formatted as dollars like default number format.
+func FormatCurrency(value float64, currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	symbol, ok := currencySymbols[currency]
+	if len(currency) == 0 {
+		symbol = currencySymbols["USD"]
+	} else if !ok {
+		symbol = currency + " "
+	}
+	return symbol + strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+// ToCurrencyNumFmt creates excel number format for currency code, nil is returned for empty code
+// so that default (dollar) number format is applied.
+func ToCurrencyNumFmt(currency string) *string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if len(currency) == 0 {
+		return nil
+	}
+
+	symbol, ok := currencySymbols[currency]
+	if !ok {
+		symbol = currency + " "
+	}
+
+	numFmt := "[$" + symbol + "]#,##0.00"
+	return &numFmt
+}
@@ -19,7 +19,34 @@ func ToPrettyString(prefix string, obj interface{}) string {
 func ToList(value string) []string {
 	var result []string
 	for _, item := range strings.Split(value, ",") {
-		result = append(result, strings.Trim(item, " "))
+		item = strings.Trim(item, " ")
+		if len(item) == 0 {
+			continue
+		}
+		result = append(result, item)
 	}
 	return result
 }
+
+func Contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func ToProjectKey(issueKey string) string {
+	if index := strings.LastIndex(issueKey, "-"); index > 0 {
+		return issueKey[:index]
+	}
+	return issueKey
+}
+
+func Mask(value string) string {
+	if len(value) <= 4 {
+		return "****"
+	}
+	return "****" + value[len(value)-4:]
+}